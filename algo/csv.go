@@ -0,0 +1,101 @@
+package algo
+
+import (
+	"io"
+
+	"github.com/vladvelici/graph-dataset-tools/util"
+)
+
+// Convenience function to read a graph from a file, by path, in any format
+// of util.OpenEdges. A third column, if any, is read as the edge weight when
+// it is a number. Binary graph files are loaded directly from their rows.
+func ReadGraph(path string) (*Graph, error) {
+	return readGraph(path, false)
+}
+
+// ReadWeightedGraph is like ReadGraph, for callers that need the weights: a
+// third column that is not a number is an error.
+func ReadWeightedGraph(path string) (*Graph, error) {
+	return readGraph(path, true)
+}
+
+func readGraph(path string, weighted bool) (*Graph, error) {
+	bin, err := util.IsBinGraph(path)
+	if err != nil {
+		return nil, err
+	}
+	if bin {
+		return readBinGraph(path)
+	}
+
+	reader, err := util.OpenEdges(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	readEdge := reader.ReadWeighted
+	if weighted {
+		readEdge = func() (int, int, float64, []string, error) { return util.ReadWeights(reader) }
+	}
+	g := NewGraph()
+	for {
+		from, to, w, _, err := readEdge()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return g, err
+		}
+		g.AddWeightedDirectedEdge(from, to, w)
+	}
+	return g, nil
+}
+
+// StreamComponents reads the edges of a file once, in any format of
+// util.OpenEdges, and puts the nodes of each weakly connected component in
+// the same set. The graph itself is never built.
+func StreamComponents(path string) (*UnionFind, error) {
+	reader, err := util.OpenEdges(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	u := NewUnionFind()
+	for {
+		from, to, _, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return u, err
+		}
+		u.Union(from, to)
+	}
+	return u, nil
+}
+
+// Load a binary graph file. Nodes are made once, up front, so edges need no
+// lookups by ID.
+func readBinGraph(path string) (*Graph, error) {
+	bg, err := util.OpenBinGraph(path)
+	if err != nil {
+		return nil, err
+	}
+	defer bg.Close()
+
+	g := NewGraph()
+	nodes := make([]*Node, bg.NumNodes())
+	for i, id := range bg.Ids {
+		nodes[i] = g.fetch(int(id))
+	}
+	for i, from := range nodes {
+		for p := bg.Offsets[i]; p < bg.Offsets[i+1]; p++ {
+			to := nodes[bg.Neighbours[p]]
+			g.directedEdge(from, to)
+			if w := bg.Weight(int(p)); w != 1 {
+				from.setWeight(to.Id, w)
+			}
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,98 @@
+package algo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// Degree is the degree of one node. In undirected graphs In and Out are the
+// same, and so is Total; in directed graphs Total is In + Out.
+type Degree struct {
+	Id    int
+	In    int
+	Out   int
+	Total int
+}
+
+// Degrees returns the degrees of every node of g, in ascending order of ID.
+func Degrees(g graph.Graph) []Degree {
+	ids := g.Nodes()
+	in := make(map[int]int, len(ids))
+	for _, id := range ids {
+		for _, to := range g.Neighbours(id) {
+			in[to]++
+		}
+	}
+	directed := g.Directed()
+	result := make([]Degree, len(ids))
+	for i, id := range ids {
+		d := Degree{Id: id, In: in[id], Out: g.Degree(id)}
+		d.Total = d.Out
+		if directed {
+			d.Total += d.In
+		}
+		result[i] = d
+	}
+	return result
+}
+
+// Bin is a bin of a histogram: the number of values from Low to High - 1.
+type Bin struct {
+	Low   int
+	High  int
+	Count int
+}
+
+// Density returns the fraction of the n values in the bin, per unit of its
+// width, so that bins of different widths can be compared.
+func (b Bin) Density(n int) float64 {
+	return float64(b.Count) / float64(n) / float64(b.High-b.Low)
+}
+
+// LogHistogram counts the non negative values in logarithmic bins: 0 on its
+// own, then from base^k to base^(k+1), rounded up, for k = 0, 1, ...
+// Bins that round to nothing are skipped. Bins go up to the largest value;
+// empty bins are kept, so that bins are the same for the same base. The
+// last bin ends at the largest int if base^(k+1) does not fit in one.
+// Returns an error unless base is finite and more than 1.
+func LogHistogram(values []int, base float64) ([]Bin, error) {
+	if !(base > 1) || math.IsInf(base, 1) {
+		return nil, fmt.Errorf("Bad histogram base %v: must be finite and more than 1.", base)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	max := 0
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+
+	bins := []Bin{{Low: 0, High: 1}}
+	low := 1
+	for k := 1; low <= max; k++ {
+		h := math.Ceil(math.Pow(base, float64(k)))
+		if h >= math.MaxInt64 {
+			bins = append(bins, Bin{Low: low, High: math.MaxInt64})
+			break
+		}
+		high := int(h)
+		if high <= low {
+			continue
+		}
+		bins = append(bins, Bin{Low: low, High: high})
+		low = high
+	}
+
+	for _, v := range values {
+		i := sort.Search(len(bins), func(i int) bool { return bins[i].High > v })
+		if i < len(bins) && v >= 0 {
+			bins[i].Count++
+		}
+	}
+	return bins, nil
+}
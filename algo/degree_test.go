@@ -0,0 +1,75 @@
+package algo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+func TestDegrees(t *testing.T) {
+	b := graph.NewBuilder()
+	b.AddDirectedEdge(1, 2)
+	b.AddDirectedEdge(1, 3)
+	b.AddDirectedEdge(3, 1)
+	d := Degrees(b.Build())
+
+	expected := []Degree{{1, 1, 2, 3}, {2, 1, 0, 1}, {3, 1, 1, 2}}
+	if len(d) != len(expected) {
+		t.Fatalf("Expected %v, got %v.", expected, d)
+	}
+	for i := range d {
+		if d[i] != expected[i] {
+			t.Errorf("Expected %v, got %v.", expected[i], d[i])
+		}
+	}
+
+	u := Degrees(mkgraph(threeConnectedGraphs).View())
+	for _, d := range u {
+		if d.In != d.Out || d.Total != d.Out {
+			t.Errorf("Undirected degrees should agree: %v.", d)
+		}
+	}
+}
+
+func TestLogHistogram(t *testing.T) {
+	bins, err := LogHistogram([]int{0, 1, 1, 2, 3, 4, 9}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Bin{{0, 1, 1}, {1, 2, 2}, {2, 4, 2}, {4, 8, 1}, {8, 16, 1}}
+	if len(bins) != len(expected) {
+		t.Fatalf("Expected %v, got %v.", expected, bins)
+	}
+	for i := range bins {
+		if bins[i] != expected[i] {
+			t.Errorf("Expected %v, got %v.", expected[i], bins[i])
+		}
+	}
+	if d := bins[2].Density(7); d != 1.0/7 {
+		t.Errorf("Expected density 1/7, got %v.", d)
+	}
+
+	// with a small base, bins that round to nothing are skipped
+	bins, _ = LogHistogram([]int{10}, 1.1)
+	for i, b := range bins {
+		if b.High <= b.Low {
+			t.Errorf("Empty bin %d: %v.", i, b)
+		}
+	}
+
+	// base^k overflowing an int ends the bins
+	bins, err = LogHistogram([]int{1 << 40}, 1e10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := bins[len(bins)-1]; last.High != math.MaxInt64 || last.Count != 1 {
+		t.Errorf("Bad last bin: %v.", last)
+	}
+
+	for _, base := range []float64{1, 0.5, math.Inf(1), math.NaN()} {
+		if _, err := LogHistogram([]int{1}, base); err == nil {
+			t.Errorf("Base %v did not return an error.", base)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+/*
+Package algo has the graph algorithms behind the conncomp tool, so that
+other programs can use them too.
+
+Graph is a mutable graph of Nodes linked by pointers, read from edge list
+files with ReadGraph. It supports connected components (ConnectedGraphs),
+traversals (Bfs, DfsEdge), spanning trees (Mst) and removing random edges
+without disconnecting the graph (RemoveRandomEdges, RemovableEdges, Folds),
+for example to make link prediction datasets.
+
+The same algorithms are also written against the read-only graph.Graph
+interface (Traverse, DfsEdges, Components, SpanningTree), and run on the
+compact graph.CSR; Graph.View adapts a Graph to that interface.
+*/
+package algo
@@ -0,0 +1,123 @@
+package algo
+
+import "github.com/vladvelici/graph-dataset-tools/graph"
+
+// CoreNumbers returns the core number of every node of g, in the order of
+// g.Nodes(): the largest k such that the node is in the k-core of g. Uses the
+// linear time algorithm of Batagelj and Zaversnik, which removes nodes in
+// order of their degree in what is left of the graph, with a bucket sort.
+// g should be undirected; self-loops do not count.
+func CoreNumbers(g graph.Graph) []int {
+	ids := g.Nodes()
+	pos := make(map[int]int, len(ids))
+	for i, id := range ids {
+		pos[id] = i
+	}
+
+	// degrees, without self-loops
+	deg := make([]int, len(ids))
+	max := 0
+	for i, id := range ids {
+		for _, n := range g.Neighbours(id) {
+			if n != id {
+				deg[i]++
+			}
+		}
+		if deg[i] > max {
+			max = deg[i]
+		}
+	}
+
+	// bucket sort of the nodes by degree: bin[d] is where the nodes of
+	// degree d start in vert, and vpos is where each node is
+	bin := make([]int, max+1)
+	for _, d := range deg {
+		bin[d]++
+	}
+	start := 0
+	for d, count := range bin {
+		bin[d] = start
+		start += count
+	}
+	vert := make([]int, len(ids))
+	vpos := make([]int, len(ids))
+	for v, d := range deg {
+		vpos[v] = bin[d]
+		vert[vpos[v]] = v
+		bin[d]++
+	}
+	for d := max; d > 0; d-- {
+		bin[d] = bin[d-1]
+	}
+	bin[0] = 0
+
+	// take the nodes by degree; each lowers the degree of its neighbours
+	// of higher degree, moving them one bucket down
+	for _, v := range vert {
+		for _, n := range g.Neighbours(ids[v]) {
+			u, ok := pos[n]
+			if !ok || u == v || deg[u] <= deg[v] {
+				continue
+			}
+			du, pu := deg[u], vpos[u]
+			pw := bin[du]
+			if w := vert[pw]; u != w {
+				vpos[u], vpos[w] = pw, pu
+				vert[pu], vert[pw] = w, u
+			}
+			bin[du]++
+			deg[u]--
+		}
+	}
+	return deg
+}
+
+// KCore returns the IDs of the nodes of the k-core of g, in ascending order:
+// the largest subgraph in which every node has at least k neighbours. g
+// should be undirected; self-loops do not count. See CoreNumbers.
+func KCore(g graph.Graph, k int) []int {
+	var result []int
+	ids := g.Nodes()
+	for i, core := range CoreNumbers(g) {
+		if core >= k {
+			result = append(result, ids[i])
+		}
+	}
+	return result
+}
+
+// CoreNumbers returns the core number of every node of g, by ID. See
+// CoreNumbers.
+func (g *Graph) CoreNumbers() map[int]int {
+	v := g.View()
+	ids := v.Nodes()
+	result := make(map[int]int, len(ids))
+	for i, core := range CoreNumbers(v) {
+		result[ids[i]] = core
+	}
+	return result
+}
+
+// KCore returns a copy of the k-core of g, weights included.
+func (g *Graph) KCore(k int) *Graph {
+	res := NewGraph()
+	core := make(map[int]bool)
+	for _, id := range KCore(g.View(), k) {
+		core[id] = true
+		res.fetch(id)
+	}
+	for id := range core {
+		node := g.Nodes[id]
+		for to := range node.Neighbours {
+			if core[to] {
+				res.AddDirectedEdge(id, to)
+			}
+		}
+		for to, w := range node.Weights {
+			if core[to] {
+				res.fetch(id).setWeight(to, w)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,113 @@
+package algo
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// A 4-clique 1-2-3-4, a triangle 4-5-6 on it, and a tail 6-7.
+func coreGraph() *graph.CSR {
+	b := graph.NewBuilder()
+	for _, e := range [][2]int{{1, 2}, {1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4}, {4, 5}, {5, 6}, {4, 6}, {6, 7}} {
+		b.AddEdge(e[0], e[1])
+	}
+	b.AddDirectedEdge(7, 7)
+	return b.Build()
+}
+
+func TestKCore(t *testing.T) {
+	g := coreGraph()
+	expected := map[int][]int{
+		0: {1, 2, 3, 4, 5, 6, 7},
+		1: {1, 2, 3, 4, 5, 6, 7},
+		2: {1, 2, 3, 4, 5, 6},
+		3: {1, 2, 3, 4},
+		4: nil,
+	}
+	for k, ids := range expected {
+		core := KCore(g, k)
+		if len(core) != len(ids) {
+			t.Errorf("%d-core: expected %v, got %v.", k, ids, core)
+			continue
+		}
+		for i := range core {
+			if core[i] != ids[i] {
+				t.Errorf("%d-core: expected %v, got %v.", k, ids, core)
+				break
+			}
+		}
+	}
+}
+
+func TestCoreNumbers(t *testing.T) {
+	cores := CoreNumbers(coreGraph())
+	expected := []int{3, 3, 3, 3, 2, 2, 1}
+	for i := range expected {
+		if cores[i] != expected[i] {
+			t.Fatalf("Expected core numbers %v, got %v.", expected, cores)
+		}
+	}
+}
+
+// Core numbers agree with removing nodes of degree less than k, for every k.
+func TestCoreNumbersPeeling(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	b := graph.NewBuilder()
+	for i := 0; i < 300; i++ {
+		b.AddEdge(1+rnd.Intn(60), 1+rnd.Intn(60))
+	}
+	g := b.Build()
+	cores := CoreNumbers(g)
+
+	for k := 0; k <= 12; k++ {
+		left := peel(g, k)
+		for i, id := range g.Nodes() {
+			if left[id] != (cores[i] >= k) {
+				t.Fatalf("%d-core: node %d has core number %d.", k, id, cores[i])
+			}
+		}
+	}
+}
+
+// The nodes left after removing nodes with less than k neighbours, over and
+// over.
+func peel(g graph.Graph, k int) map[int]bool {
+	left := make(map[int]bool)
+	for _, id := range g.Nodes() {
+		left[id] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for id := range left {
+			deg := 0
+			for _, n := range g.Neighbours(id) {
+				if n != id && left[n] {
+					deg++
+				}
+			}
+			if deg < k {
+				delete(left, id)
+				changed = true
+			}
+		}
+	}
+	return left
+}
+
+func TestGraphKCore(t *testing.T) {
+	g := NewGraph()
+	g.AddWeightedEdge(1, 2, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(3, 1)
+	g.AddEdge(3, 4)
+
+	if c := g.CoreNumbers(); c[1] != 2 || c[4] != 1 {
+		t.Errorf("Unexpected core numbers %v.", c)
+	}
+	core := g.KCore(2)
+	if len(core.Nodes) != 3 || core.HasEdge(3, 4) || core.Weight(2, 1) != 2 {
+		t.Errorf("Unexpected 2-core %v.", core.NodeIds())
+	}
+}
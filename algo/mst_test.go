@@ -0,0 +1,59 @@
+package algo
+
+import "testing"
+
+func TestMstSet(t *testing.T) {
+	mst := make(Mst)
+
+	mst.Add(4, 3)
+	if !mst.Has(3, 4) {
+		t.Error("simple. Does not have links from 3 to 4.")
+	}
+	if !mst.Has(4, 3) {
+		t.Error("simple. Does not have links from 4 to 3.")
+	}
+
+	mst.Add(3, 10)
+
+	if !mst.Has(10, 3) || !mst.Has(3, 10) {
+		t.Error("second. Does not have a link.")
+	}
+
+}
+
+func TestSpanningForests(t *testing.T) {
+	g := NewGraph()
+	g.AddWeightedEdge(1, 2, 1)
+	g.AddWeightedEdge(2, 3, 5)
+	g.AddWeightedEdge(3, 4, 1)
+	g.AddWeightedEdge(4, 1, 2)
+	g.AddWeightedEdge(1, 3, 3)
+	g.AddEdge(5, 6)
+
+	expected := map[string][][2]int{
+		"min": {{1, 2}, {3, 4}, {1, 4}, {5, 6}},
+		"max": {{2, 3}, {1, 3}, {1, 4}, {5, 6}},
+	}
+	for name, forest := range map[string]Mst{"min": g.Mst(), "max": g.MaxSt()} {
+		count := 0
+		for _, to := range forest {
+			count += len(to)
+		}
+		if count != len(expected[name]) {
+			t.Errorf("%s: expected %d edges, got %d.", name, len(expected[name]), count)
+		}
+		for _, e := range expected[name] {
+			if !forest.Has(e[0], e[1]) {
+				t.Errorf("%s: expected edge %d - %d in the forest.", name, e[0], e[1])
+			}
+		}
+	}
+}
+
+func TestRemoveKeepsComponents(t *testing.T) {
+	g := mkgraph(threeConnectedGraphs)
+	g.RemoveRandomEdges(len(g.EdgeList()), g.Mst())
+	if n := len(g.ConnectedGraphs()); n != 3 {
+		t.Errorf("Expected the 3 components to stay, got %d.", n)
+	}
+}
@@ -0,0 +1,110 @@
+package algo
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// How SampleNonEdges picks node pairs.
+type SampleMode int
+
+const (
+	// Both nodes uniformly at random.
+	Uniform SampleMode = iota
+	// Both nodes with probability proportional to their degree, so the
+	// non-edges have degrees like the real edges.
+	DegreeMatched
+	// The first node uniformly, the second among the nodes at most hops away.
+	WithinHops
+)
+
+// ParseSampleMode parses the names used on the command line: uniform, degree, hops.
+func ParseSampleMode(name string) (SampleMode, error) {
+	switch name {
+	case "uniform":
+		return Uniform, nil
+	case "degree":
+		return DegreeMatched, nil
+	case "hops":
+		return WithinHops, nil
+	}
+	return Uniform, fmt.Errorf("Unknown sampling mode %q. Use uniform, degree or hops.", name)
+}
+
+// SampleNonEdges draws n distinct node pairs that are not edges in either
+// direction, using rnd. Nodes are taken in ID order, so that sampling with
+// a seeded source is reproducible. Gives up early if it keeps drawing edges, as on
+// nearly complete graphs, so fewer than n pairs may be returned.
+// hops is only used by WithinHops.
+func (g *Graph) SampleNonEdges(n int, mode SampleMode, hops int, rnd *rand.Rand) []*Edge {
+	result := make([]*Edge, 0, n)
+	ids := g.NodeIds()
+	if len(ids) < 2 {
+		return result
+	}
+
+	var pick func() (int, int, bool)
+	switch mode {
+	case DegreeMatched:
+		// cumulative degrees, to pick by binary search
+		cumulative := make([]int, len(ids))
+		total := 0
+		for i, id := range ids {
+			total += g.Degree(id)
+			cumulative[i] = total
+		}
+		if total == 0 {
+			return result
+		}
+		byDegree := func() int {
+			r := rnd.Intn(total)
+			return ids[sort.SearchInts(cumulative, r+1)]
+		}
+		pick = func() (int, int, bool) {
+			return byDegree(), byDegree(), true
+		}
+	case WithinHops:
+		v := g.View()
+		pick = func() (int, int, bool) {
+			from := ids[rnd.Intn(len(ids))]
+			near := within(v, from, hops)
+			if len(near) == 0 {
+				return 0, 0, false
+			}
+			return from, near[rnd.Intn(len(near))], true
+		}
+	default:
+		pick = func() (int, int, bool) {
+			return ids[rnd.Intn(len(ids))], ids[rnd.Intn(len(ids))], true
+		}
+	}
+
+	seen := make(Mst)
+	for misses := 0; len(result) < n && misses < 100*n+100; {
+		from, to, ok := pick()
+		if !ok || from == to || seen.Has(from, to) || g.HasEdge(from, to) || g.HasEdge(to, from) {
+			misses++
+			continue
+		}
+		seen.Add(from, to)
+		result = append(result, &Edge{from, to})
+	}
+
+	return result
+}
+
+// IDs of the nodes at distance 2 to hops from root, sorted.
+func within(g graph.Graph, root int, hops int) []int {
+	dist, _ := BfsDistancesWithin(g, root, hops)
+	var result []int
+	for id, d := range dist {
+		if d >= 2 {
+			result = append(result, id)
+		}
+	}
+	sort.Ints(result)
+	return result
+}
@@ -0,0 +1,65 @@
+package algo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSampleNonEdges(t *testing.T) {
+	g := mkgraph(connectedGraph)
+
+	for _, mode := range []SampleMode{Uniform, DegreeMatched, WithinHops} {
+		sample := g.SampleNonEdges(10, mode, 2, rand.New(rand.NewSource(1)))
+		if len(sample) != 10 {
+			t.Errorf("Mode %d: expected 10 non-edges, got %d.", mode, len(sample))
+		}
+
+		seen := make(Mst)
+		for _, e := range sample {
+			if e.From == e.To {
+				t.Errorf("Mode %d: self loop %#v.", mode, *e)
+			}
+			if g.HasEdge(e.From, e.To) || g.HasEdge(e.To, e.From) {
+				t.Errorf("Mode %d: %#v is an edge.", mode, *e)
+			}
+			if seen.Has(e.From, e.To) {
+				t.Errorf("Mode %d: %#v sampled twice.", mode, *e)
+			}
+			seen.Add(e.From, e.To)
+		}
+	}
+}
+
+func TestSampleNonEdgesSeed(t *testing.T) {
+	g := mkgraph(connectedGraph)
+	for _, mode := range []SampleMode{Uniform, DegreeMatched, WithinHops} {
+		a := g.SampleNonEdges(5, mode, 2, rand.New(rand.NewSource(7)))
+		b := g.SampleNonEdges(5, mode, 2, rand.New(rand.NewSource(7)))
+		for i := range a {
+			if *a[i] != *b[i] {
+				t.Errorf("Mode %d: same seed, different samples %v and %v.", mode, *a[i], *b[i])
+			}
+		}
+	}
+}
+
+func TestSampleNonEdgesWithinHops(t *testing.T) {
+	// a path 0 - 1 - 2 - 3 - 4: within 2 hops only pairs at distance exactly 2
+	g := mkgraph([][]int{{1}, {2}, {3}, {4}})
+	sample := g.SampleNonEdges(10, WithinHops, 2, rand.New(rand.NewSource(1)))
+	if len(sample) != 3 {
+		t.Errorf("Only 3 pairs are 2 hops apart, got %d.", len(sample))
+	}
+	for _, e := range sample {
+		if d := e.To - e.From; d != 2 && d != -2 {
+			t.Errorf("%#v is not 2 hops apart.", *e)
+		}
+	}
+}
+
+func TestSampleNonEdgesComplete(t *testing.T) {
+	g := mkgraph([][]int{{1, 2}, {2}})
+	if sample := g.SampleNonEdges(3, Uniform, 0, rand.New(rand.NewSource(1))); len(sample) != 0 {
+		t.Errorf("A triangle has no non-edges, got %d.", len(sample))
+	}
+}
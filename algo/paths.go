@@ -0,0 +1,195 @@
+package algo
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// BfsDistances returns the number of edges on a shortest path from root to
+// every node reached from it, and the node before each on such a path. The
+// parent of root is root itself.
+func BfsDistances(g graph.Graph, root int) (dist, parent map[int]int) {
+	return bfs(g, root, -1)
+}
+
+// BfsDistancesWithin is like BfsDistances, but only goes as far as the nodes
+// k edges away from root.
+func BfsDistancesWithin(g graph.Graph, root, k int) (dist, parent map[int]int) {
+	return bfs(g, root, k)
+}
+
+// Neighbourhood returns the IDs of the nodes at most k edges away from root,
+// root included, in ascending order.
+func Neighbourhood(g graph.Graph, root, k int) []int {
+	dist, _ := bfs(g, root, k)
+	ids := make([]int, 0, len(dist))
+	for id := range dist {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Breadth first search from root, up to limit edges away unless limit is
+// negative.
+func bfs(g graph.Graph, root, limit int) (dist, parent map[int]int) {
+	dist = map[int]int{root: 0}
+	parent = map[int]int{root: root}
+	todo := []int{root}
+	for len(todo) > 0 {
+		id := todo[0]
+		todo = todo[1:]
+		if dist[id] == limit {
+			continue
+		}
+		for _, n := range g.Neighbours(id) {
+			if _, ok := dist[n]; !ok {
+				dist[n] = dist[id] + 1
+				parent[n] = id
+				todo = append(todo, n)
+			}
+		}
+	}
+	return dist, parent
+}
+
+// HopDistance returns the number of edges on a shortest path from one node to
+// another, or -1 if there is none. In undirected graphs it searches from both
+// ends at once, a level at a time from the smaller side, so it only explores
+// around the two nodes; in directed graphs it searches from the first node.
+func HopDistance(g graph.Graph, from, to int) int {
+	if from == to {
+		return 0
+	}
+	if g.Directed() {
+		return hopsForward(g, from, to)
+	}
+
+	dist := [2]map[int]int{{from: 0}, {to: 0}}
+	levels := [2][]int{{from}, {to}}
+	for len(levels[0]) > 0 && len(levels[1]) > 0 {
+		side := 0
+		if len(levels[1]) < len(levels[0]) {
+			side = 1
+		}
+		mine, other := dist[side], dist[1-side]
+
+		best := -1
+		var next []int
+		for _, id := range levels[side] {
+			for _, n := range g.Neighbours(id) {
+				if d, ok := other[n]; ok {
+					if total := mine[id] + 1 + d; best < 0 || total < best {
+						best = total
+					}
+				}
+				if _, ok := mine[n]; !ok {
+					mine[n] = mine[id] + 1
+					next = append(next, n)
+				}
+			}
+		}
+		if best >= 0 {
+			return best
+		}
+		levels[side] = next
+	}
+	return -1
+}
+
+// Breadth first search from one node until another is reached.
+func hopsForward(g graph.Graph, from, to int) int {
+	dist := map[int]int{from: 0}
+	todo := []int{from}
+	for len(todo) > 0 {
+		id := todo[0]
+		todo = todo[1:]
+		for _, n := range g.Neighbours(id) {
+			if _, ok := dist[n]; ok {
+				continue
+			}
+			if n == to {
+				return dist[id] + 1
+			}
+			dist[n] = dist[id] + 1
+			todo = append(todo, n)
+		}
+	}
+	return -1
+}
+
+// Dijkstra returns the total weight of a lightest path from root to every
+// node reached from it, and the node before each on such a path. The parent
+// of root is root itself. Edges without weights weigh 1; weights must not be
+// negative.
+func Dijkstra(g graph.Graph, root int) (dist map[int]float64, parent map[int]int) {
+	dist, parent = dijkstra(g, root, func(int) bool { return false })
+	return dist, parent
+}
+
+// WeightedDistance returns the total weight of a lightest path from one node
+// to another, or +Inf if there is none. The search stops once the other node
+// is reached.
+func WeightedDistance(g graph.Graph, from, to int) float64 {
+	dist, _ := dijkstra(g, from, func(id int) bool { return id == to })
+	if d, ok := dist[to]; ok {
+		return d
+	}
+	return math.Inf(1)
+}
+
+// Dijkstra's algorithm from root, until a node settled satisfies stop.
+// Distances of nodes not settled yet are not final.
+func dijkstra(g graph.Graph, root int, stop func(id int) bool) (map[int]float64, map[int]int) {
+	dist := map[int]float64{root: 0}
+	parent := map[int]int{root: root}
+	done := make(map[int]bool)
+	todo := &distHeap{{root, 0}}
+	for todo.Len() > 0 {
+		item := heap.Pop(todo).(distItem)
+		if done[item.id] {
+			continue
+		}
+		done[item.id] = true
+		if stop(item.id) {
+			break
+		}
+		for _, n := range g.Neighbours(item.id) {
+			d := item.dist + graph.WeightOf(g, item.id, n)
+			if old, ok := dist[n]; !ok || d < old {
+				dist[n] = d
+				parent[n] = item.id
+				heap.Push(todo, distItem{n, d})
+			}
+		}
+	}
+	return dist, parent
+}
+
+// A node and its distance, in the queue of Dijkstra's algorithm.
+type distItem struct {
+	id   int
+	dist float64
+}
+
+// Min-heap of distItems by distance, then ID.
+type distHeap []distItem
+
+func (h distHeap) Len() int { return len(h) }
+func (h distHeap) Less(i, j int) bool {
+	if h[i].dist != h[j].dist {
+		return h[i].dist < h[j].dist
+	}
+	return h[i].id < h[j].id
+}
+func (h distHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *distHeap) Push(x interface{}) { *h = append(*h, x.(distItem)) }
+func (h *distHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,94 @@
+package algo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// A path 1-2-3-4-5, a shortcut 1-6-5, and 7 on its own.
+func pathGraph() *graph.CSR {
+	b := graph.NewBuilder()
+	b.AddWeightedEdge(1, 2, 1)
+	b.AddWeightedEdge(2, 3, 1)
+	b.AddWeightedEdge(3, 4, 1)
+	b.AddWeightedEdge(4, 5, 1)
+	b.AddWeightedEdge(1, 6, 5)
+	b.AddWeightedEdge(6, 5, 5)
+	b.AddNode(7)
+	return b.Build()
+}
+
+func TestBfsDistances(t *testing.T) {
+	dist, parent := BfsDistances(pathGraph(), 1)
+	if dist[5] != 2 || parent[5] != 6 || parent[1] != 1 || dist[3] != 2 {
+		t.Errorf("Unexpected distances %v, parents %v.", dist, parent)
+	}
+	if _, ok := dist[7]; ok {
+		t.Error("7 should not be reached.")
+	}
+}
+
+func TestHopDistance(t *testing.T) {
+	g := pathGraph()
+	cases := [][3]int{{1, 5, 2}, {2, 4, 2}, {3, 6, 3}, {4, 4, 0}, {1, 7, -1}}
+	for _, c := range cases {
+		if d := HopDistance(g, c[0], c[1]); d != c[2] {
+			t.Errorf("Expected %d hops from %d to %d, got %d.", c[2], c[0], c[1], d)
+		}
+	}
+
+	// all pairs agree with breadth first search
+	for _, from := range g.Nodes() {
+		dist, _ := BfsDistances(g, from)
+		for _, to := range g.Nodes() {
+			expected, ok := dist[to]
+			if !ok {
+				expected = -1
+			}
+			if d := HopDistance(g, from, to); d != expected {
+				t.Errorf("From %d to %d: expected %d, got %d.", from, to, expected, d)
+			}
+		}
+	}
+
+	b := graph.NewBuilder()
+	b.AddDirectedEdge(1, 2)
+	b.AddDirectedEdge(2, 3)
+	d := b.Build()
+	if HopDistance(d, 1, 3) != 2 || HopDistance(d, 3, 1) != -1 {
+		t.Error("Directed edges should only be followed forwards.")
+	}
+}
+
+func TestDijkstra(t *testing.T) {
+	g := pathGraph()
+	dist, parent := Dijkstra(g, 1)
+	if dist[5] != 4 || parent[5] != 4 || dist[6] != 5 {
+		t.Errorf("Unexpected distances %v, parents %v.", dist, parent)
+	}
+	if d := WeightedDistance(g, 6, 2); d != 6 {
+		t.Errorf("Expected distance 6, got %v.", d)
+	}
+	if d := WeightedDistance(g, 1, 7); !math.IsInf(d, 1) {
+		t.Errorf("Expected no path, got %v.", d)
+	}
+}
+
+func TestNeighbourhood(t *testing.T) {
+	g := pathGraph()
+	ids := Neighbourhood(g, 2, 2)
+	expected := []int{1, 2, 3, 4, 6}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected %v, got %v.", expected, ids)
+	}
+	for i := range ids {
+		if ids[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v.", expected, ids)
+		}
+	}
+	if ids := Neighbourhood(g, 7, 3); len(ids) != 1 || ids[0] != 7 {
+		t.Errorf("Expected only 7, got %v.", ids)
+	}
+}
@@ -0,0 +1,106 @@
+package algo
+
+import (
+	"sort"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// StronglyConnected returns the strongly connected components of the
+// directed graph g: the largest sets of nodes that all have paths to each
+// other. Uses Tarjan's algorithm, without recursion so that long paths do
+// not overflow the stack.
+// Like Components, components are ordered by their smallest ID, and their
+// IDs are sorted.
+func StronglyConnected(g graph.Graph) [][]int {
+	ids := g.Nodes()
+	pos := make(map[int]int, len(ids))
+	for i, id := range ids {
+		pos[id] = i
+	}
+
+	const unvisited = -1
+	index := make([]int, len(ids))
+	low := make([]int, len(ids))
+	onStack := make([]bool, len(ids))
+	for i := range index {
+		index[i] = unvisited
+	}
+
+	// a frame of the simulated recursion: node v, and its next neighbour
+	type frame struct {
+		v, next int
+	}
+	var result [][]int
+	var stack []int
+	counter := 0
+
+	for root := range ids {
+		if index[root] != unvisited {
+			continue
+		}
+		calls := []frame{{root, 0}}
+		index[root], low[root] = counter, counter
+		counter++
+		stack = append(stack, root)
+		onStack[root] = true
+
+		for len(calls) > 0 {
+			top := &calls[len(calls)-1]
+			v := top.v
+			neighbours := g.Neighbours(ids[v])
+
+			if top.next < len(neighbours) {
+				w, ok := pos[neighbours[top.next]]
+				top.next++
+				if !ok {
+					continue
+				}
+				if index[w] == unvisited {
+					index[w], low[w] = counter, counter
+					counter++
+					stack = append(stack, w)
+					onStack[w] = true
+					calls = append(calls, frame{w, 0})
+				} else if onStack[w] && index[w] < low[v] {
+					low[v] = index[w]
+				}
+				continue
+			}
+
+			// all neighbours done: v is the root of a component, or passes
+			// its low link to its parent
+			calls = calls[:len(calls)-1]
+			if low[v] == index[v] {
+				var comp []int
+				for {
+					w := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					onStack[w] = false
+					comp = append(comp, ids[w])
+					if w == v {
+						break
+					}
+				}
+				sort.Ints(comp)
+				result = append(result, comp)
+			}
+			if len(calls) > 0 {
+				parent := calls[len(calls)-1].v
+				if low[v] < low[parent] {
+					low[parent] = low[v]
+				}
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i][0] < result[j][0] })
+	return result
+}
+
+// WeaklyConnected returns the weakly connected components of the directed
+// graph g: the connected components when edges are followed both ways.
+// Components are ordered like in Components.
+func WeaklyConnected(g graph.Graph) [][]int {
+	return Components(graph.Undirected(g))
+}
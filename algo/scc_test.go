@@ -0,0 +1,59 @@
+package algo
+
+import (
+	"testing"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+func directed(edges [][2]int) graph.Graph {
+	b := graph.NewBuilder()
+	for _, e := range edges {
+		b.AddDirectedEdge(e[0], e[1])
+	}
+	return b.Build()
+}
+
+func sameComponents(t *testing.T, got, expected [][]int) {
+	if len(got) != len(expected) {
+		t.Fatalf("Expected components %v, got %v.", expected, got)
+	}
+	for i := range got {
+		if len(got[i]) != len(expected[i]) {
+			t.Fatalf("Expected components %v, got %v.", expected, got)
+		}
+		for j := range got[i] {
+			if got[i][j] != expected[i][j] {
+				t.Fatalf("Expected components %v, got %v.", expected, got)
+			}
+		}
+	}
+}
+
+func TestStronglyConnected(t *testing.T) {
+	// cycle 1-2-3, cycle 4-5 reached from it, 6 only reached, 7 alone
+	g := directed([][2]int{{1, 2}, {2, 3}, {3, 1}, {3, 4}, {4, 5}, {5, 4}, {5, 6}, {7, 7}})
+	sameComponents(t, StronglyConnected(g), [][]int{{1, 2, 3}, {4, 5}, {6}, {7}})
+}
+
+func TestStronglyConnectedLongPath(t *testing.T) {
+	// one cycle through many nodes, a deep recursion for a recursive Tarjan
+	var edges [][2]int
+	n := 100000
+	for i := 1; i < n; i++ {
+		edges = append(edges, [2]int{i, i + 1})
+	}
+	edges = append(edges, [2]int{n, 1})
+	comps := StronglyConnected(directed(edges))
+	if len(comps) != 1 || len(comps[0]) != n {
+		t.Errorf("Expected one component of %d nodes, got %d components.", n, len(comps))
+	}
+}
+
+func TestWeaklyConnected(t *testing.T) {
+	g := directed([][2]int{{1, 2}, {3, 2}, {4, 5}})
+	sameComponents(t, WeaklyConnected(g), [][]int{{1, 2, 3}, {4, 5}})
+
+	// forwards only, 3 cannot be reached from 1
+	sameComponents(t, Components(g), [][]int{{1, 2}, {3}, {4, 5}})
+}
@@ -0,0 +1,48 @@
+package algo
+
+import (
+	"sort"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// MinimumSpanningForest returns a minimum spanning tree of every component of
+// g, found with Kruskal's algorithm. Edges are followed both ways, and an
+// edge without weights weighs 1. Edges of the same weight are taken in order
+// of their nodes, so the forest is the same on every run.
+func MinimumSpanningForest(g graph.Graph) Mst {
+	return kruskal(g, func(a, b float64) bool { return a < b })
+}
+
+// MaximumSpanningForest is like MinimumSpanningForest, but the trees have the
+// largest total weight instead.
+func MaximumSpanningForest(g graph.Graph) Mst {
+	return kruskal(g, func(a, b float64) bool { return a > b })
+}
+
+// Kruskal's algorithm: take the edges in the order of their weights by
+// less, keeping those that join two trees.
+func kruskal(g graph.Graph, less func(a, b float64) bool) Mst {
+	type arc struct {
+		from, to int
+		weight   float64
+	}
+	var arcs []arc
+	for _, from := range g.Nodes() {
+		for _, to := range g.Neighbours(from) {
+			if from != to {
+				arcs = append(arcs, arc{from, to, graph.WeightOf(g, from, to)})
+			}
+		}
+	}
+	sort.SliceStable(arcs, func(i, j int) bool { return less(arcs[i].weight, arcs[j].weight) })
+
+	res := make(Mst)
+	trees := NewUnionFind()
+	for _, a := range arcs {
+		if trees.Union(a.from, a.to) {
+			res.Add(a.from, a.to)
+		}
+	}
+	return res
+}
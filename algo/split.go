@@ -0,0 +1,178 @@
+package algo
+
+import (
+	"bufio"
+	"encoding/gob"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/vladvelici/graph-dataset-tools/util"
+)
+
+// Most files open at once by SplitComponents. A variable for the tests.
+var maxOpenFiles = 256
+
+// SplitComponents splits the edge list at path into its weakly connected
+// components without loading the graph: one pass over the edges to find the
+// components, and one to copy each edge, as it is, to the file of its
+// component, name(i) for the i-th component, in the format of
+// util.CreateEdges. With more than maxOpenFiles components, the second pass
+// spreads the edges over at most maxOpenFiles temporary files, each with a
+// range of components, and these are split the same way.
+func SplitComponents(path string, name func(i int) string) error {
+	u, err := StreamComponents(path)
+	if err != nil {
+		return err
+	}
+	sets := u.Sets()
+	number := make(map[int]int, len(sets)) // representative -> component number
+	for i, set := range sets {
+		number[u.Find(set[0])] = i
+	}
+
+	reader, err := util.OpenEdges(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	dir, err := ioutil.TempDir("", "components")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	s := &splitter{name: name, u: u, number: number, dir: dir}
+	return s.split(reader.Read, 0, len(sets))
+}
+
+// Copies the edges of a file to the files of their components.
+type splitter struct {
+	name   func(i int) string
+	u      *UnionFind
+	number map[int]int // representative -> component number
+	dir    string      // of the temporary files
+	temps  int         // temporary files made so far
+}
+
+// Where split writes edges: a component file or a temporary file.
+type edgeSink interface {
+	Write(a, b int, rubbish []string) error
+	Close() error
+}
+
+// Copy the edges returned by next, all in components first to last-1, to
+// the files of their components, or to temporary files of ranges of
+// components when there are more than maxOpenFiles of them.
+func (s *splitter) split(next func() (int, int, []string, error), first, last int) error {
+	width := (last - first + maxOpenFiles - 1) / maxOpenFiles // components per file
+	var sinks []edgeSink
+	defer func() {
+		for _, w := range sinks {
+			w.Close()
+		}
+	}()
+	var temps []string
+	for start := first; start < last; start += width {
+		var w edgeSink
+		var err error
+		if width == 1 {
+			w, err = util.CreateEdges(s.name(start))
+		} else {
+			path := filepath.Join(s.dir, strconv.Itoa(s.temps))
+			s.temps++
+			temps = append(temps, path)
+			w, err = createTempEdges(path)
+		}
+		if err != nil {
+			return err
+		}
+		sinks = append(sinks, w)
+	}
+
+	for {
+		from, to, rubbish, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		i := (s.number[s.u.Find(from)] - first) / width
+		if err := sinks[i].Write(from, to, rubbish); err != nil {
+			return err
+		}
+	}
+	for _, w := range sinks {
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+	sinks = nil
+
+	for i, path := range temps {
+		start := first + i*width
+		end := start + width
+		if end > last {
+			end = last
+		}
+		if err := s.splitTemp(path, start, end); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Split the temporary file at path, with the edges of components first to
+// last-1, and remove it.
+func (s *splitter) splitTemp(path string, first, last int) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	defer file.Close()
+	dec := gob.NewDecoder(bufio.NewReader(file))
+	return s.split(func() (int, int, []string, error) {
+		var e tempEdge
+		err := dec.Decode(&e)
+		return e.From, e.To, e.Rubbish, err
+	}, first, last)
+}
+
+// An edge and its other columns, as kept in temporary files. Gob keeps the
+// columns as they are, whatever the format of the input.
+type tempEdge struct {
+	From, To int
+	Rubbish  []string
+}
+
+// Temporary file of edges, in gob.
+type tempEdges struct {
+	file *os.File
+	buf  *bufio.Writer
+	enc  *gob.Encoder
+}
+
+func createTempEdges(path string) (*tempEdges, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	buf := bufio.NewWriter(file)
+	return &tempEdges{file, buf, gob.NewEncoder(buf)}, nil
+}
+
+func (w *tempEdges) Write(a, b int, rubbish []string) error {
+	return w.enc.Encode(tempEdge{a, b, rubbish})
+}
+
+func (w *tempEdges) Close() error {
+	err := w.buf.Flush()
+	if cerr := w.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package algo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// Split in.csv, with content input, in a temporary directory. Component i
+// is written to i.csv in the same directory, which is returned.
+func splitInTempDir(t *testing.T, input string) string {
+	dir, err := ioutil.TempDir("", "algo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := filepath.Join(dir, "in.csv")
+	if err := ioutil.WriteFile(in, []byte(input), 0644); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	name := func(i int) string { return filepath.Join(dir, strconv.Itoa(i)+".csv") }
+	if err := SplitComponents(in, name); err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestSplitComponents(t *testing.T) {
+	dir := splitInTempDir(t, "4,5,x\n1,2,a\n3,2,b\n5,4,y\n")
+	defer os.RemoveAll(dir)
+
+	expected := map[string]string{
+		"0.csv": "1,2,a\n3,2,b\n",
+		"1.csv": "4,5,x\n5,4,y\n",
+	}
+	for fname, content := range expected {
+		raw, err := ioutil.ReadFile(filepath.Join(dir, fname))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(raw) != content {
+			t.Errorf("Expected %q in %s, got %q.", content, fname, raw)
+		}
+	}
+}
+
+// More components than open files go through temporary files, twice here.
+func TestSplitManyComponents(t *testing.T) {
+	defer func(n int) { maxOpenFiles = n }(maxOpenFiles)
+	maxOpenFiles = 2
+
+	// components {1, 2}, {3, 4}, ... {9, 10}, edges of each spread out
+	input := ""
+	for _, rubbish := range []string{"a", "b"} {
+		for i := 1; i < 10; i += 2 {
+			input += strconv.Itoa(i) + "," + strconv.Itoa(i+1) + "," + rubbish + "\n"
+		}
+	}
+	dir := splitInTempDir(t, input)
+	defer os.RemoveAll(dir)
+
+	for c := 0; c < 5; c++ {
+		fname := filepath.Join(dir, strconv.Itoa(c)+".csv")
+		raw, err := ioutil.ReadFile(fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, b := strconv.Itoa(2*c+1), strconv.Itoa(2*c+2)
+		if expected := a + "," + b + ",a\n" + a + "," + b + ",b\n"; string(raw) != expected {
+			t.Errorf("Expected %q in %s, got %q.", expected, fname, raw)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package algo
+
+import (
+	"math"
+	"sort"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// Stats are summary statistics of a graph. Degrees are numbers of
+// neighbours, out-degrees in directed graphs. Components, clustering and
+// diameter are those of the undirected graph, with edges followed both ways.
+type Stats struct {
+	Nodes       int
+	Edges       int // undirected edges count once
+	Directed    bool
+	Components  int
+	Largest     int // nodes in the largest component
+	SelfLoops   int
+	Density     float64 // edges over possible edges, self-loops excluded
+	Reciprocity float64 // fraction of edges with a reverse edge, self-loops excluded
+
+	MinDegree    int
+	MaxDegree    int
+	MeanDegree   float64
+	MedianDegree float64
+	PowerLaw     float64 // maximum likelihood exponent of the degrees; 0 if there are none
+
+	Triangles    int
+	Clustering   float64 // average local clustering coefficient
+	Transitivity float64 // global clustering coefficient
+	Diameter     int     // lower bound of the diameter of the largest component
+}
+
+// Statistics computes the Stats of g.
+func Statistics(g graph.Graph) Stats {
+	s := Stats{Nodes: len(g.Nodes()), Directed: g.Directed()}
+
+	degrees := make([]int, 0, s.Nodes)
+	arcs, reciprocated := 0, 0
+	for _, id := range g.Nodes() {
+		degrees = append(degrees, g.Degree(id))
+		for _, to := range g.Neighbours(id) {
+			if to == id {
+				s.SelfLoops++
+				continue
+			}
+			arcs++
+			if g.HasEdge(to, id) {
+				reciprocated++
+			}
+		}
+	}
+
+	s.Edges = arcs + s.SelfLoops
+	possible := float64(s.Nodes) * float64(s.Nodes-1)
+	if !s.Directed {
+		s.Edges = arcs/2 + s.SelfLoops
+		possible /= 2
+	}
+	if possible > 0 {
+		s.Density = float64(s.Edges-s.SelfLoops) / possible
+	}
+	if arcs > 0 {
+		s.Reciprocity = float64(reciprocated) / float64(arcs)
+	}
+
+	if len(degrees) > 0 {
+		sort.Ints(degrees)
+		s.MinDegree, s.MaxDegree = degrees[0], degrees[len(degrees)-1]
+		total := 0
+		for _, d := range degrees {
+			total += d
+		}
+		s.MeanDegree = float64(total) / float64(len(degrees))
+		mid := len(degrees) / 2
+		s.MedianDegree = float64(degrees[mid])
+		if len(degrees)%2 == 0 {
+			s.MedianDegree = float64(degrees[mid-1]+degrees[mid]) / 2
+		}
+		s.PowerLaw = powerLaw(degrees)
+	}
+
+	u := graph.Undirected(g)
+	comps := WeaklyConnected(g)
+	s.Components = len(comps)
+	if len(comps) > 0 {
+		BySize(comps)
+		s.Largest = len(comps[0])
+		s.Diameter = diameter(u, comps[0][0])
+	}
+	c := ExactClustering(u, 0)
+	s.Triangles, s.Clustering, s.Transitivity = c.Total, c.Average, c.Global
+	return s
+}
+
+// Estimate the exponent of a power law fitted to the positive degrees, by
+// the discrete maximum likelihood approximation of Clauset et al., with the
+// smallest positive degree as the lower bound.
+func powerLaw(degrees []int) float64 {
+	min := 0
+	for _, d := range degrees {
+		if d > 0 && (min == 0 || d < min) {
+			min = d
+		}
+	}
+	n, sum := 0, 0.0
+	for _, d := range degrees {
+		if d >= min && min > 0 {
+			n++
+			sum += math.Log(float64(d) / (float64(min) - 0.5))
+		}
+	}
+	if sum == 0 {
+		return 0
+	}
+	return 1 + float64(n)/sum
+}
+
+// Estimate the diameter of the component of root in g by a double sweep:
+// the eccentricity of the node farthest from root. It is a lower bound, and
+// exact on trees.
+func diameter(g graph.Graph, root int) int {
+	far, _ := farthest(g, root)
+	_, dist := farthest(g, far)
+	return dist
+}
+
+// The node farthest from root in g, the one with the smallest ID among ties,
+// and its distance from root.
+func farthest(g graph.Graph, root int) (int, int) {
+	dist, _ := BfsDistances(g, root)
+	far := root
+	for id, d := range dist {
+		if d > dist[far] || (d == dist[far] && id < far) {
+			far = id
+		}
+	}
+	return far, dist[far]
+}
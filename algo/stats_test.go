@@ -0,0 +1,54 @@
+package algo
+
+import (
+	"math"
+	"testing"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+func TestStatistics(t *testing.T) {
+	// a triangle with a tail, a self-loop at its end, and a separate edge
+	b := graph.NewBuilder()
+	b.AddEdge(1, 2)
+	b.AddEdge(2, 3)
+	b.AddEdge(1, 3)
+	b.AddEdge(3, 4)
+	b.AddDirectedEdge(4, 4)
+	b.AddEdge(6, 7)
+	s := Statistics(b.Build())
+
+	if s.Nodes != 6 || s.Edges != 6 || s.Directed || s.SelfLoops != 1 {
+		t.Errorf("Expected 6 nodes, 6 undirected edges, 1 self-loop, got %+v.", s)
+	}
+	if s.Components != 2 || s.Largest != 4 || s.Diameter != 2 {
+		t.Errorf("Expected 2 components, the largest of 4 nodes and diameter 2, got %+v.", s)
+	}
+	if s.MinDegree != 1 || s.MaxDegree != 3 || s.MedianDegree != 2 || math.Abs(s.MeanDegree-11.0/6) > 1e-9 {
+		t.Errorf("Wrong degree summary: %+v.", s)
+	}
+	if math.Abs(s.Density-1.0/3) > 1e-9 || s.Reciprocity != 1 {
+		t.Errorf("Expected density 1/3 and reciprocity 1, got %v and %v.", s.Density, s.Reciprocity)
+	}
+	if math.Abs(s.Clustering-(1+1+1.0/3)/6) > 1e-9 || s.Triangles != 1 || math.Abs(s.Transitivity-3.0/5) > 1e-9 {
+		t.Errorf("Expected clustering 7/18, 1 triangle and transitivity 3/5, got %+v.", s)
+	}
+	if s.PowerLaw <= 1 {
+		t.Errorf("Expected a power law exponent above 1, got %v.", s.PowerLaw)
+	}
+}
+
+func TestStatisticsDirected(t *testing.T) {
+	b := graph.NewBuilder()
+	b.AddEdge(1, 2)
+	b.AddDirectedEdge(2, 3)
+	b.AddDirectedEdge(3, 4)
+	s := Statistics(b.Build())
+
+	if !s.Directed || s.Edges != 4 || s.Reciprocity != 0.5 || s.Components != 1 || s.Diameter != 3 {
+		t.Errorf("Unexpected statistics %+v.", s)
+	}
+	if math.Abs(s.Density-4.0/12) > 1e-9 {
+		t.Errorf("Expected density 1/3, got %v.", s.Density)
+	}
+}
@@ -0,0 +1,392 @@
+package algo
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// Type to represent an edge set.
+type Edge struct {
+	From, To int
+}
+
+// Represents a node in a graph.
+type Node struct {
+	Id         int
+	Neighbours map[int]*Node
+	// Weights of the edges to neighbours, when not 1. Nil until needed.
+	Weights map[int]float64
+}
+
+// Create a new node.
+func NewNode(id int) *Node {
+	return &Node{
+		id,
+		make(map[int]*Node),
+		nil,
+	}
+}
+
+// Set the weight of the edge to node to. Only weights other than 1 are stored.
+func (n *Node) setWeight(to int, w float64) {
+	if w == 1 {
+		delete(n.Weights, to)
+		return
+	}
+	if n.Weights == nil {
+		n.Weights = make(map[int]float64)
+	}
+	n.Weights[to] = w
+}
+
+// Represents an undirected graph that forms connected graphs.
+type Graph struct {
+	Nodes map[int]*Node
+	view  *graph.CSR // cached by View, nil after a change
+}
+
+// Create a new graph.
+func NewGraph() *Graph {
+	return &Graph{
+		Nodes: make(map[int]*Node),
+	}
+}
+
+// Fetches the node by ID. Creates the node if it does not exist.
+func (g *Graph) fetch(node int) *Node {
+	f, ok := g.Nodes[node]
+	if !ok || f == nil {
+		f = NewNode(node)
+		g.Nodes[node] = f
+		g.view = nil
+	}
+	return f
+}
+
+// List of edges to be printed out.
+func (g *Graph) EdgeList() []*Edge {
+	res := make([]*Edge, 0)
+	for nid, node := range g.Nodes {
+		for toId, _ := range node.Neighbours {
+			res = append(res, &Edge{nid, toId})
+		}
+	}
+	return res
+}
+
+func (g *Graph) EachEdge(f func(from, to *Node) bool) {
+	for _, from := range g.Nodes {
+		for _, to := range from.Neighbours {
+			if ok := f(from, to); !ok {
+				return
+			}
+		}
+	}
+}
+
+func (g *Graph) AddDirectedEdge(fromId, toId int) {
+	from := g.fetch(fromId)
+	to := g.fetch(toId)
+	g.directedEdge(from, to)
+}
+
+// Add a directed edge, taking care of connected components.
+// Panics if either from or to are nil.
+func (g *Graph) directedEdge(from, to *Node) {
+	if _, ok := from.Neighbours[to.Id]; ok {
+		return
+	}
+	from.Neighbours[to.Id] = to
+	g.view = nil
+}
+
+// Add an undirected edge to the graph.
+func (g *Graph) AddEdge(fromId, toId int) {
+	from := g.fetch(fromId)
+	to := g.fetch(toId)
+	g.directedEdge(from, to)
+	g.directedEdge(to, from)
+}
+
+// Add a directed edge with a weight. Overwrites the weight if the edge exists.
+func (g *Graph) AddWeightedDirectedEdge(fromId, toId int, w float64) {
+	from := g.fetch(fromId)
+	to := g.fetch(toId)
+	g.directedEdge(from, to)
+	from.setWeight(toId, w)
+	g.view = nil
+}
+
+// Add an undirected edge with a weight (in both directions).
+func (g *Graph) AddWeightedEdge(fromId, toId int, w float64) {
+	g.AddWeightedDirectedEdge(fromId, toId, w)
+	g.AddWeightedDirectedEdge(toId, fromId, w)
+}
+
+// Weight of the edge from -> to: 1 unless set by a weighted Add.
+func (g *Graph) Weight(from, to int) float64 {
+	if node, ok := g.Nodes[from]; ok {
+		if w, ok := node.Weights[to]; ok {
+			return w
+		}
+	}
+	return 1
+}
+
+// IsWeighted returns whether any edge has a weight other than 1.
+func (g *Graph) IsWeighted() bool {
+	for _, node := range g.Nodes {
+		if len(node.Weights) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Add a node to the graph. Used internally for connected components.
+func (g *Graph) addNode(node *Node) {
+	g.Nodes[node.Id] = node
+	g.view = nil
+}
+
+// Internal type used for traversals.
+type tr_index map[int]interface{}
+
+// Set node as visited.
+func (t tr_index) visit(node *Node) {
+	t[node.Id] = nil
+}
+
+// Check if a node was visited.
+func (t tr_index) visited(node *Node) bool {
+	_, ok := t[node.Id]
+	return ok
+}
+
+// Returns whether the given graph is connected.
+func (g *Graph) IsConnected() bool {
+	return IsConnected(g.View())
+}
+
+// Is undirected
+func (g *Graph) IsUndirected() bool {
+	return !graph.IsDirected(g.View())
+}
+
+// Return a list of connected graphs, ordered by their smallest node ID.
+// The components share their nodes with g: changes made through them are
+// not seen by the View of g.
+func (g *Graph) ConnectedGraphs() []*Graph {
+	result := make([]*Graph, 0)
+	for _, ids := range Components(g.View()) {
+		graph := NewGraph()
+		for _, id := range ids {
+			graph.addNode(g.Nodes[id])
+		}
+		result = append(result, graph)
+	}
+	return result
+}
+
+// View returns a snapshot of g as a graph.Graph. It is a graph.CSR, so that
+// the algorithms get sorted neighbours without allocating, and Directed is
+// only worked out once. The snapshot is kept until g is changed through its
+// methods; changes made to Nodes directly are not seen.
+func (g *Graph) View() graph.Graph {
+	if g.view == nil {
+		g.view = g.CSR()
+	}
+	return g.view
+}
+
+// CSR returns a copy of g as a graph.CSR, weights included.
+func (g *Graph) CSR() *graph.CSR {
+	b := graph.NewBuilder()
+	for id, node := range g.Nodes {
+		b.AddNode(id)
+		for to := range node.Neighbours {
+			b.AddWeightedDirectedEdge(id, to, g.Weight(id, to))
+		}
+	}
+	return b.Build()
+}
+
+// NodeIds returns the IDs of all nodes, in ascending order.
+func (g *Graph) NodeIds() []int {
+	ids := make([]int, 0, len(g.Nodes))
+	for id := range g.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// Neighbours returns the IDs of the neighbours of id, in ascending order.
+func (g *Graph) Neighbours(id int) []int {
+	node, ok := g.Nodes[id]
+	if !ok {
+		return nil
+	}
+	res := make([]int, 0, len(node.Neighbours))
+	for to := range node.Neighbours {
+		res = append(res, to)
+	}
+	sort.Ints(res)
+	return res
+}
+
+// Degree returns the number of edges from id.
+func (g *Graph) Degree(id int) int {
+	if node, ok := g.Nodes[id]; ok {
+		return len(node.Neighbours)
+	}
+	return 0
+}
+
+// HasEdge returns whether the edge from -> to exists.
+func (g *Graph) HasEdge(from, to int) bool {
+	node, ok := g.Nodes[from]
+	if !ok {
+		return false
+	}
+	_, ok = node.Neighbours[to]
+	return ok
+}
+
+// Mst is a set of undirected edges, such as the edges of a spanning tree or
+// forest. An edge and its reverse are the same edge.
+type Mst map[int]map[int]interface{}
+
+// Add adds the edge from - to. It is stored with from < to.
+func (m Mst) Add(from, to int) {
+	if from > to {
+		from, to = to, from
+	}
+	f := m[from]
+	if f == nil {
+		m[from] = make(map[int]interface{})
+	}
+	m[from][to] = nil
+}
+
+// Has returns whether the edge from - to, or its reverse, is in the set.
+func (m Mst) Has(from, to int) bool {
+	if from > to {
+		from, to = to, from
+	}
+	if f, ok := m[from]; ok && f != nil {
+		_, ok2 := f[to]
+		return ok2
+	}
+	return false
+}
+
+// Mst returns a minimum spanning forest of g: a minimum spanning tree of
+// each of its components, by edge weight. See MinimumSpanningForest.
+func (g *Graph) Mst() Mst {
+	return MinimumSpanningForest(g.View())
+}
+
+// MaxSt returns a maximum spanning forest of g. See MaximumSpanningForest.
+func (g *Graph) MaxSt() Mst {
+	return MaximumSpanningForest(g.View())
+}
+
+// Remove random edges, keeping track of them.
+//
+// Edges in restrictions are never removed. With a spanning forest of g as
+// restrictions (see Mst), every component of g stays connected.
+//
+// Uses the global math/rand source; see RemoveRandomEdgesRand for reproducible results.
+func (g *Graph) RemoveRandomEdges(n int, restrictions Mst) []*Edge {
+	return g.RemoveRandomEdgesRand(n, restrictions, rand.New(rand.NewSource(rand.Int63())))
+}
+
+// RemoveRandomEdgesRand removes at most n random undirected edges that are
+// not in restrictions, choosing them with rnd. Returns the removed edges.
+func (g *Graph) RemoveRandomEdgesRand(n int, restrictions Mst, rnd *rand.Rand) []*Edge {
+	candidates := g.RemovableEdges(restrictions)
+	if n > len(candidates) {
+		n = len(candidates)
+	}
+
+	// partial Fisher-Yates shuffle
+	for i := 0; i < n; i++ {
+		j := i + rnd.Intn(len(candidates)-i)
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	}
+	result := candidates[:n]
+
+	g.RemoveEdges(result)
+	return result
+}
+
+// RemovableEdges lists the undirected edges that are not in restrictions,
+// once each (with From < To, unless it is a self loop), sorted.
+func (g *Graph) RemovableEdges(restrictions Mst) []*Edge {
+	seen := make(Mst)
+	result := make([]*Edge, 0)
+	for _, from := range g.NodeIds() {
+		for _, to := range g.Neighbours(from) {
+			if seen.Has(from, to) || restrictions.Has(from, to) {
+				continue
+			}
+			seen.Add(from, to)
+			if from < to {
+				result = append(result, &Edge{from, to})
+			} else {
+				result = append(result, &Edge{to, from})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].From != result[j].From {
+			return result[i].From < result[j].From
+		}
+		return result[i].To < result[j].To
+	})
+	return result
+}
+
+// RemoveEdges removes the given edges, in both directions, with their
+// weights.
+func (g *Graph) RemoveEdges(edges []*Edge) {
+	for _, edge := range edges {
+		if node, ok := g.Nodes[edge.From]; ok {
+			delete(node.Neighbours, edge.To)
+			delete(node.Weights, edge.To)
+		}
+		if node, ok := g.Nodes[edge.To]; ok {
+			delete(node.Neighbours, edge.From)
+			delete(node.Weights, edge.From)
+		}
+	}
+	g.view = nil
+}
+
+// Copy returns a deep copy of the graph, weights included.
+func (g *Graph) Copy() *Graph {
+	res := NewGraph()
+	for id, node := range g.Nodes {
+		res.fetch(id)
+		for to := range node.Neighbours {
+			res.AddDirectedEdge(id, to)
+		}
+		for to, w := range node.Weights {
+			res.fetch(id).setWeight(to, w)
+		}
+	}
+	return res
+}
+
+// Folds splits edges into k folds of (almost) equal size, after shuffling
+// them with rnd.
+func Folds(edges []*Edge, k int, rnd *rand.Rand) [][]*Edge {
+	folds := make([][]*Edge, k)
+	for i, j := range rnd.Perm(len(edges)) {
+		folds[i%k] = append(folds[i%k], edges[j])
+	}
+	return folds
+}
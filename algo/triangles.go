@@ -0,0 +1,195 @@
+package algo
+
+import (
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// Clustering holds the triangles and clustering coefficients of a graph.
+// Per node values are in the order of g.Nodes().
+type Clustering struct {
+	Triangles []int     // triangles each node is in
+	Local     []float64 // fraction of pairs of neighbours that are neighbours
+	Total     int       // triangles in the graph
+	Average   float64   // average of Local; nodes with less than two neighbours count as 0
+	Global    float64   // fraction of paths of two edges that are closed: 3 Total / paths
+}
+
+// ExactClustering counts the triangles of g, and from them the clustering
+// coefficients. Each triangle is found once, from its node of smallest
+// degree, by intersecting the lists of neighbours of higher degree. The
+// nodes are shared between workers goroutines, or one per CPU if workers is
+// not positive. g should be undirected; self-loops do not count.
+func ExactClustering(g graph.Graph, workers int) Clustering {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	ids := g.Nodes()
+	pos := make(map[int]int, len(ids))
+	for i, id := range ids {
+		pos[id] = i
+	}
+	neighbours := make([][]int, len(ids))
+	for i, id := range ids {
+		neighbours[i] = distinctNeighbours(g, id, pos)
+	}
+
+	// orient every edge towards its node of higher degree, ties by ID
+	higher := func(a, b int) bool {
+		da, db := len(neighbours[a]), len(neighbours[b])
+		return da < db || (da == db && a < b)
+	}
+	forward := make([][]int, len(ids))
+	for i, ns := range neighbours {
+		for _, j := range ns {
+			if higher(i, j) {
+				forward[i] = append(forward[i], j)
+			}
+		}
+	}
+
+	counts := make([]int64, len(ids))
+	var next int64 = -1
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				u := int(atomic.AddInt64(&next, 1))
+				if u >= len(ids) {
+					return
+				}
+				for _, v := range forward[u] {
+					intersect(forward[u], forward[v], func(w int) {
+						atomic.AddInt64(&counts[u], 1)
+						atomic.AddInt64(&counts[v], 1)
+						atomic.AddInt64(&counts[w], 1)
+					})
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	triangles := make([]float64, len(ids))
+	for i, c := range counts {
+		triangles[i] = float64(c)
+	}
+	return clustering(neighbours, triangles)
+}
+
+// ApproximateClustering estimates the clustering coefficients of g by
+// checking, for every node, at most samples random pairs of its neighbours,
+// chosen with rnd. Nodes with no more pairs than that are counted exactly.
+// Triangle counts are rounded estimates. g should be undirected.
+func ApproximateClustering(g graph.Graph, samples int, rnd *rand.Rand) Clustering {
+	ids := g.Nodes()
+	neighbours := make([][]int, len(ids))
+	triangles := make([]float64, len(ids))
+	for i, id := range ids {
+		ns := distinctNeighbours(g, id, nil)
+		neighbours[i] = ns
+		k := len(ns)
+		pairs := k * (k - 1) / 2
+		if k < 2 {
+			continue
+		}
+
+		closed := 0
+		if pairs <= samples {
+			for a := range ns {
+				for _, b := range ns[a+1:] {
+					if g.HasEdge(ns[a], b) {
+						closed++
+					}
+				}
+			}
+			triangles[i] = float64(closed)
+			continue
+		}
+		for s := 0; s < samples; s++ {
+			a := rnd.Intn(k)
+			b := rnd.Intn(k - 1)
+			if b >= a {
+				b++
+			}
+			if g.HasEdge(ns[a], ns[b]) {
+				closed++
+			}
+		}
+		triangles[i] = float64(closed) / float64(samples) * float64(pairs)
+	}
+	return clustering(neighbours, triangles)
+}
+
+// The neighbours of id, without id itself, in ascending order. With pos,
+// they are given as positions in pos instead of IDs, and those not in pos
+// are left out.
+func distinctNeighbours(g graph.Graph, id int, pos map[int]int) []int {
+	var result []int
+	for _, n := range g.Neighbours(id) {
+		if n == id {
+			continue
+		}
+		if pos == nil {
+			result = append(result, n)
+		} else if p, ok := pos[n]; ok {
+			result = append(result, p)
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+// Call f with every value in both a and b, which are sorted.
+func intersect(a, b []int, f func(int)) {
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			f(a[i])
+			i++
+			j++
+		}
+	}
+}
+
+// Clustering coefficients from the neighbours of each node and the number
+// of triangles it is in.
+func clustering(neighbours [][]int, triangles []float64) Clustering {
+	c := Clustering{
+		Triangles: make([]int, len(triangles)),
+		Local:     make([]float64, len(triangles)),
+	}
+	var closed, paths float64
+	for i, t := range triangles {
+		k := len(neighbours[i])
+		c.Triangles[i] = int(math.Floor(t + 0.5))
+		if k < 2 {
+			continue
+		}
+		pairs := float64(k*(k-1)) / 2
+		c.Local[i] = t / pairs
+		c.Average += c.Local[i]
+		closed += t
+		paths += pairs
+	}
+	if len(triangles) > 0 {
+		c.Average /= float64(len(triangles))
+	}
+	if paths > 0 {
+		c.Global = closed / paths
+	}
+	c.Total = int(math.Floor(closed/3 + 0.5))
+	return c
+}
@@ -0,0 +1,73 @@
+package algo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+func randomGraph(nodes, edges int, seed int64) *graph.CSR {
+	rnd := rand.New(rand.NewSource(seed))
+	b := graph.NewBuilder()
+	for i := 0; i < edges; i++ {
+		b.AddEdge(1+rnd.Intn(nodes), 1+rnd.Intn(nodes))
+	}
+	return b.Build()
+}
+
+func TestExactClustering(t *testing.T) {
+	g := coreGraph()
+	c := ExactClustering(g, 2)
+	// the 4-clique has 4 triangles, and 4-5-6 one more
+	expected := []int{3, 3, 3, 4, 1, 1, 0}
+	for i := range expected {
+		if c.Triangles[i] != expected[i] {
+			t.Fatalf("Expected triangles %v, got %v.", expected, c.Triangles)
+		}
+	}
+	if c.Total != 5 || c.Local[0] != 1 || c.Local[3] != 0.4 || c.Local[6] != 0 {
+		t.Errorf("Unexpected clustering %+v.", c)
+	}
+}
+
+// Triangle counts agree with checking every pair of neighbours, whatever the
+// number of goroutines.
+func TestExactClusteringWorkers(t *testing.T) {
+	g := randomGraph(80, 600, 5)
+	ids := g.Nodes()
+	for _, workers := range []int{1, 3, 0} {
+		c := ExactClustering(g, workers)
+		for i, id := range ids {
+			pairs := 0
+			ns := distinctNeighbours(g, id, nil)
+			for a := range ns {
+				for _, b := range ns[a+1:] {
+					if g.HasEdge(ns[a], b) {
+						pairs++
+					}
+				}
+			}
+			if c.Triangles[i] != pairs {
+				t.Fatalf("%d workers: node %d is in %d triangles, got %d.", workers, id, pairs, c.Triangles[i])
+			}
+		}
+	}
+}
+
+func TestApproximateClustering(t *testing.T) {
+	g := randomGraph(80, 600, 5)
+	exact := ExactClustering(g, 0)
+
+	// with enough samples every node is counted exactly
+	all := ApproximateClustering(g, 1000, rand.New(rand.NewSource(1)))
+	if all.Total != exact.Total || math.Abs(all.Global-exact.Global) > 1e-9 {
+		t.Errorf("Expected %d triangles, got %d.", exact.Total, all.Total)
+	}
+
+	some := ApproximateClustering(g, 50, rand.New(rand.NewSource(1)))
+	if math.Abs(some.Global-exact.Global) > 0.05 {
+		t.Errorf("Expected global clustering near %v, got %v.", exact.Global, some.Global)
+	}
+}
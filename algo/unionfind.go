@@ -0,0 +1,106 @@
+package algo
+
+import "sort"
+
+// UnionFind is a disjoint-set forest over node IDs, with path compression
+// and union by size. It needs memory for the nodes only, not for the edges,
+// so it can find the components of graphs streamed edge by edge.
+type UnionFind struct {
+	parent map[int]int
+	size   map[int]int
+	sets   int
+}
+
+// NewUnionFind creates an empty UnionFind.
+func NewUnionFind() *UnionFind {
+	return &UnionFind{
+		parent: make(map[int]int),
+		size:   make(map[int]int),
+	}
+}
+
+// Add adds id as a set of its own, if it is not in a set yet.
+func (u *UnionFind) Add(id int) {
+	if _, ok := u.parent[id]; ok {
+		return
+	}
+	u.parent[id] = id
+	u.size[id] = 1
+	u.sets++
+}
+
+// Find returns the representative of the set of id, adding id if needed.
+func (u *UnionFind) Find(id int) int {
+	u.Add(id)
+	root := id
+	for u.parent[root] != root {
+		root = u.parent[root]
+	}
+	for id != root {
+		next := u.parent[id]
+		u.parent[id] = root
+		id = next
+	}
+	return root
+}
+
+// Union merges the sets of a and b. Returns false if they already were in
+// the same set.
+func (u *UnionFind) Union(a, b int) bool {
+	a, b = u.Find(a), u.Find(b)
+	if a == b {
+		return false
+	}
+	if u.size[a] < u.size[b] {
+		a, b = b, a
+	}
+	u.parent[b] = a
+	u.size[a] += u.size[b]
+	delete(u.size, b)
+	u.sets--
+	return true
+}
+
+// Same returns whether a and b are in the same set.
+func (u *UnionFind) Same(a, b int) bool {
+	return u.Find(a) == u.Find(b)
+}
+
+// Size returns the number of IDs in the set of id.
+func (u *UnionFind) Size(id int) int {
+	return u.size[u.Find(id)]
+}
+
+// Len returns the number of IDs added.
+func (u *UnionFind) Len() int {
+	return len(u.parent)
+}
+
+// Count returns the number of sets.
+func (u *UnionFind) Count() int {
+	return u.sets
+}
+
+// Sets returns the IDs of each set, ordered like in Components: by their
+// smallest ID, with their IDs sorted.
+func (u *UnionFind) Sets() [][]int {
+	ids := make([]int, 0, len(u.parent))
+	for id := range u.parent {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	number := make(map[int]int, u.sets)
+	var result [][]int
+	for _, id := range ids {
+		root := u.Find(id)
+		i, ok := number[root]
+		if !ok {
+			i = len(result)
+			number[root] = i
+			result = append(result, nil)
+		}
+		result[i] = append(result[i], id)
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package algo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnionFind(t *testing.T) {
+	u := NewUnionFind()
+	u.Union(5, 1)
+	u.Union(2, 3)
+	u.Union(3, 5)
+	u.Add(4)
+	if u.Union(1, 2) {
+		t.Error("1 and 2 should already be in the same set.")
+	}
+	if !u.Same(1, 3) || u.Same(1, 4) {
+		t.Error("Wrong sets.")
+	}
+	if u.Len() != 5 || u.Count() != 2 || u.Size(2) != 4 || u.Size(4) != 1 {
+		t.Errorf("Expected 5 IDs in 2 sets, got %d IDs in %d sets.", u.Len(), u.Count())
+	}
+	sameComponents(t, u.Sets(), [][]int{{1, 2, 3, 5}, {4}})
+}
+
+func TestStreamComponents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "algo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// directed edges, with 3 only reached backwards
+	in := filepath.Join(dir, "in.txt")
+	if err := ioutil.WriteFile(in, []byte("1 2\n3 2\n4 5\n6 6\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	u, err := StreamComponents(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameComponents(t, u.Sets(), [][]int{{1, 2, 3}, {4, 5}, {6}})
+}
@@ -0,0 +1,110 @@
+package algo
+
+// Graph algorithms written against graph.Graph, so that they work both on
+// the mutable Graph (through Graph.View) and on the compact graph.CSR.
+
+import (
+	"sort"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// Breadth first traversal of g from root, calling f on every node reached.
+// Nodes already in visited are skipped, and reached nodes are added to it,
+// so one visited set can be shared by several traversals.
+func Traverse(g graph.Graph, root int, visited map[int]bool, f func(id int)) {
+	if visited[root] {
+		return
+	}
+	visited[root] = true
+	todo := []int{root}
+	for len(todo) > 0 {
+		id := todo[0]
+		todo = todo[1:]
+		f(id)
+		for _, ngh := range g.Neighbours(id) {
+			if !visited[ngh] {
+				visited[ngh] = true
+				todo = append(todo, ngh)
+			}
+		}
+	}
+}
+
+// Depth first traversal of g from root, visiting neighbours in ascending
+// order. f is called with each node reached and the node it was reached
+// from (root is reached from itself).
+func DfsEdges(g graph.Graph, root int, visited map[int]bool, f func(from, to int)) {
+	todo := []int{root}
+	parents := []int{root}
+	for len(todo) > 0 {
+		id, parent := todo[len(todo)-1], parents[len(parents)-1]
+		todo, parents = todo[:len(todo)-1], parents[:len(parents)-1]
+
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		f(parent, id)
+
+		neighbours := g.Neighbours(id)
+		for i := len(neighbours) - 1; i >= 0; i-- {
+			if !visited[neighbours[i]] {
+				todo = append(todo, neighbours[i])
+				parents = append(parents, id)
+			}
+		}
+	}
+}
+
+// Components returns the node IDs of each connected component of g, by
+// following edges from the smallest node ID not reached yet. g should be
+// undirected. Components are ordered by their smallest ID, and their IDs
+// are sorted.
+func Components(g graph.Graph) [][]int {
+	visited := make(map[int]bool)
+	var result [][]int
+	for _, id := range g.Nodes() {
+		if visited[id] {
+			continue
+		}
+		var comp []int
+		Traverse(g, id, visited, func(n int) { comp = append(comp, n) })
+		sort.Ints(comp)
+		result = append(result, comp)
+	}
+	return result
+}
+
+// BySize sorts components from the largest to the smallest. Components of
+// the same size keep their order.
+func BySize(comps [][]int) {
+	sort.SliceStable(comps, func(i, j int) bool { return len(comps[i]) > len(comps[j]) })
+}
+
+// IsConnected returns whether every node of g is reached from its first node.
+func IsConnected(g graph.Graph) bool {
+	ids := g.Nodes()
+	if len(ids) == 0 {
+		return true
+	}
+	count := 0
+	Traverse(g, ids[0], make(map[int]bool), func(int) { count++ })
+	return count == len(ids)
+}
+
+// SpanningTree returns a spanning tree of the component of g with the
+// smallest node ID, found depth first. It is the same on every run.
+func SpanningTree(g graph.Graph) Mst {
+	ids := g.Nodes()
+	if len(ids) == 0 {
+		return nil
+	}
+	res := make(Mst)
+	DfsEdges(g, ids[0], make(map[int]bool), func(from, to int) {
+		if from != to {
+			res.Add(from, to)
+		}
+	})
+	return res
+}
@@ -0,0 +1,77 @@
+package algo
+
+import (
+	"testing"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// The algorithms should give the same answers on both representations.
+func TestViewsAgree(t *testing.T) {
+	g := mkgraph(threeConnectedGraphs)
+	v, c := g.View(), g.CSR()
+
+	gc, cc := Components(v), Components(c)
+	if len(gc) != 3 || len(cc) != 3 {
+		t.Fatalf("Expected 3 components, got %d and %d.", len(gc), len(cc))
+	}
+	for i := range gc {
+		if len(gc[i]) != len(cc[i]) || gc[i][0] != cc[i][0] {
+			t.Errorf("Component %d differs: %v and %v.", i, gc[i], cc[i])
+		}
+	}
+
+	if v.Directed() || c.Directed() || IsConnected(c) {
+		t.Error("Both graphs should be undirected and disconnected.")
+	}
+	if graph.NumEdges(v) != c.NumEdges() {
+		t.Errorf("Expected %d edges, got %d.", graph.NumEdges(v), c.NumEdges())
+	}
+
+	gt, ct := SpanningTree(v), SpanningTree(c)
+	for from, tos := range gt {
+		for to := range tos {
+			if !ct.Has(from, to) {
+				t.Errorf("Spanning trees differ at (%d, %d).", from, to)
+			}
+		}
+	}
+}
+
+func TestViewCached(t *testing.T) {
+	g := NewGraph()
+	g.AddEdge(1, 2)
+	v := g.View()
+	if g.View() != v {
+		t.Error("View was built again without a change.")
+	}
+
+	g.AddEdge(2, 3)
+	if v = g.View(); !v.HasEdge(3, 2) {
+		t.Error("View does not see an added edge.")
+	}
+	g.RemoveEdges([]*Edge{{1, 2}})
+	if v = g.View(); v.HasEdge(1, 2) {
+		t.Error("View still has a removed edge.")
+	}
+	g.AddWeightedEdge(2, 3, 4)
+	if w := graph.WeightOf(g.View(), 2, 3); w != 4 {
+		t.Errorf("View does not see a new weight, got %v.", w)
+	}
+}
+
+func TestCSRIsolatedNodes(t *testing.T) {
+	g := NewGraph()
+	g.AddEdge(1, 2)
+	g.fetch(3)
+	c := g.CSR()
+	if len(c.Nodes()) != 3 || len(Components(c)) != 2 {
+		t.Errorf("Expected 3 nodes in 2 components, got %v.", Components(c))
+	}
+}
+
+func TestBySize(t *testing.T) {
+	comps := [][]int{{1}, {2, 3}, {4}, {5, 6, 7}}
+	BySize(comps)
+	sameComponents(t, comps, [][]int{{5, 6, 7}, {2, 3}, {1}, {4}})
+}
@@ -0,0 +1,57 @@
+package algo
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWeights(t *testing.T) {
+	g := NewGraph()
+	g.AddEdge(1, 2)
+	if g.IsWeighted() {
+		t.Error("Graph without weights is weighted.")
+	}
+	if w := g.Weight(1, 2); w != 1 {
+		t.Errorf("Default weight should be 1, got %v.", w)
+	}
+
+	g.AddWeightedEdge(2, 3, 2.5)
+	if !g.IsWeighted() {
+		t.Error("Graph with weights is not weighted.")
+	}
+	if g.Weight(2, 3) != 2.5 || g.Weight(3, 2) != 2.5 {
+		t.Errorf("Expected weight 2.5 both ways, got %v and %v.", g.Weight(2, 3), g.Weight(3, 2))
+	}
+
+	c := g.Copy()
+	g.AddWeightedEdge(2, 3, 4)
+	if c.Weight(2, 3) != 2.5 {
+		t.Errorf("Copy shares weights with the original, got %v.", c.Weight(2, 3))
+	}
+	c.RemoveEdges([]*Edge{{2, 3}})
+	if c.IsWeighted() || c.Weight(3, 2) != 1 {
+		t.Errorf("Removed edges should lose their weights, got %v.", c.Weight(3, 2))
+	}
+}
+
+func TestReadWeightedGraph(t *testing.T) {
+	dir, err := ioutil.TempDir("", "algo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in.csv")
+	if err := ioutil.WriteFile(in, []byte("1,2,0.5\n2,1,0.5\n2,3,3\n3,2,3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := ReadGraph(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Weight(1, 2) != 0.5 || g.Weight(3, 2) != 3 {
+		t.Errorf("Weights not read: %v, %v.", g.Weight(1, 2), g.Weight(3, 2))
+	}
+}
@@ -2,6 +2,10 @@
 Processing inputs of form:
 node1, node2, [whatever]
 
+in any edge list format of util.OpenEdges (CSV, whitespace separated, Matrix
+Market, gzipped), picked by the file extension. Outputs have the name, and so
+the format, of their input, except that .txt files are always written as CSV.
+
 and outputting things of form:
 node1_processed, node2_processed, [whatever]
 
@@ -110,18 +114,16 @@ func apply(indexPath string, files []string, prefix string) error {
 	}
 
 	for _, file := range files {
-		input, err := os.Open(file)
+		inputCsv, err := util.OpenEdges(file)
 		if err != nil {
 			return err
 		}
-		output, err := os.Create(prefix + file)
+		outputCsv, err := util.CreateEdges(prefix + file)
 		if err != nil {
+			inputCsv.Close()
 			return err
 		}
 
-		inputCsv := util.NewReader(input)
-		outputCsv := util.NewWriter(output)
-
 		for {
 			a, b, pass, err := inputCsv.Read()
 			if err == io.EOF {
@@ -147,10 +149,7 @@ func apply(indexPath string, files []string, prefix string) error {
 			}
 		}
 
-		ferr, err1, err2 := outputCsv.Flush(), input.Close(), output.Close()
-		if ferr != nil {
-			return ferr
-		}
+		err1, err2 := inputCsv.Close(), outputCsv.Close()
 		if err1 != nil {
 			return err1
 		}
@@ -187,18 +186,16 @@ func revert(indexPath string, files []string, prefix string) error {
 	}
 
 	for _, file := range files {
-		input, err := os.Open(file)
+		inputCsv, err := util.OpenEdges(file)
 		if err != nil {
 			return err
 		}
-		output, err := os.Create(prefix + file)
+		outputCsv, err := util.CreateEdges(prefix + file)
 		if err != nil {
+			inputCsv.Close()
 			return err
 		}
 
-		inputCsv := util.NewReader(input)
-		outputCsv := util.NewWriter(output)
-
 		for {
 			a, b, pass, err := inputCsv.Read()
 			if err == io.EOF {
@@ -224,10 +221,7 @@ func revert(indexPath string, files []string, prefix string) error {
 			}
 		}
 
-		ferr, err1, err2 := outputCsv.Flush(), input.Close(), output.Close()
-		if ferr != nil {
-			return ferr
-		}
+		err1, err2 := inputCsv.Close(), outputCsv.Close()
 		if err1 != nil {
 			return err1
 		}
@@ -257,18 +251,16 @@ func mkapply(indexPath string, files []string, prefix string) error {
 	index := NewMapping()
 
 	for _, file := range files {
-		input, err := os.Open(file)
+		inputCsv, err := util.OpenEdges(file)
 		if err != nil {
 			return err
 		}
-		output, err := os.Create(prefix + file)
+		outputCsv, err := util.CreateEdges(prefix + file)
 		if err != nil {
+			inputCsv.Close()
 			return err
 		}
 
-		inputCsv := util.NewReader(input)
-		outputCsv := util.NewWriter(output)
-
 		for {
 			a, b, pass, err := inputCsv.Read()
 			if err == io.EOF {
@@ -286,10 +278,7 @@ func mkapply(indexPath string, files []string, prefix string) error {
 			}
 		}
 
-		ferr, err1, err2 := outputCsv.Flush(), input.Close(), output.Close()
-		if ferr != nil {
-			return ferr
-		}
+		err1, err2 := inputCsv.Close(), outputCsv.Close()
 		if err1 != nil {
 			return err1
 		}
@@ -315,13 +304,11 @@ func index(indexPath string, files []string, prefix string) error {
 	index := NewMapping()
 
 	for _, file := range files {
-		input, err := os.Open(file)
+		inputCsv, err := util.OpenEdges(file)
 		if err != nil {
 			return err
 		}
 
-		inputCsv := util.NewReader(input)
-
 		for {
 			a, b, _, err := inputCsv.Read()
 			if err == io.EOF {
@@ -334,7 +321,7 @@ func index(indexPath string, files []string, prefix string) error {
 			b, _ = index.Node(b)
 		}
 
-		if err = input.Close(); err != nil {
+		if err = inputCsv.Close(); err != nil {
 			return err
 		}
 	}
@@ -1,23 +1,48 @@
 package main
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"math"
+	"math/rand"
 	"os"
 	"strconv"
+	"text/tabwriter"
 
+	"github.com/vladvelici/graph-dataset-tools/algo"
+	"github.com/vladvelici/graph-dataset-tools/graph"
 	"github.com/vladvelici/graph-dataset-tools/util"
 )
 
 // Define flags.
 var (
-	flagAction  = flag.String("action", "", "Possibile actions: details, components, remove")
+	flagAction  = flag.String("action", "", "Possibile actions: details, degree, clustering, distance, kcore, components, scc, wcc, largest, remove, force-undirected")
 	flagN       = flag.Float64("n", 0, "\\% of edges to remove from each graph.")
 	flagOutput  = flag.String("o", "component_", "Output file prefix. It will be followed by the component number and an underscore.")
 	flagVerbose = flag.Bool("verbose", false, "Whether to print lots of debug information on stdout.")
 	flagAll     = flag.Bool("all", false, "Whether to print all edges (to->from and from->to) when removing, in the removed list.")
 	flagForce   = flag.Bool("force", false, "Do not make safety checks. Might be faster.")
+	flagNeg     = flag.Int("neg", 0, "Number of non-edges to sample when removing. They are written to a nonedges_ file.")
+	flagNegMode = flag.String("negmode", "uniform", "How to sample non-edges: uniform, degree (degree-matched) or hops (within -hops of each other).")
+	flagHops    = flag.Int("hops", 2, "Maximum distance between the nodes of a non-edge, for -negmode hops.")
+	flagSeed    = flag.Int64("seed", 1, "Seed for the random number generator used when removing edges and sampling non-edges.")
+	flagFolds   = flag.Int("folds", 0, "With remove, split the removable edges into this many folds, each written as a train/test pair.")
+	flagRepeat  = flag.Int("repeat", 0, "With remove, make this many independent random splits of -n P% edges each.")
+	flagTop     = flag.Int("top", 1, "With largest, the number of largest components to write.")
+	flagMinSize = flag.Int("minsize", 0, "With largest, write all components with at least this many nodes instead of -top.")
+	flagStream  = flag.Bool("stream", false, "With components, read the edges twice instead of loading the graph.")
+	flagFormat  = flag.String("format", "table", "Output of details: table, json or csv.")
+	flagBase    = flag.Float64("base", 2, "With degree, the base of the logarithmic histogram bins.")
+	flagMaxSt   = flag.Bool("maxst", false, "With remove, keep a maximum spanning forest instead of a minimum one, by edge weight.")
+	flagPairs   = flag.String("pairs", "", "With distance, the edge list of node pairs to find the distances of.")
+	flagWeights = flag.Bool("weighted", false, "With distance, add up edge weights instead of counting edges.")
+	flagK       = flag.Int("k", 0, "With kcore, also write the k-core of the graph(s) for this k.")
+	flagSamples = flag.Int("samples", 0, "With clustering, estimate from at most this many pairs of neighbours per node, instead of counting all triangles.")
+	flagWorkers = flag.Int("workers", 0, "With clustering, the number of goroutines counting triangles; 0 for one per CPU.")
 	flagHelp    = flag.Bool("help", false, "Show this help message")
 	flagH       = flag.Bool("h", false, "Show this help message")
 )
@@ -30,9 +55,40 @@ conncomp -action <action> [flags] <list of edge lists>
 
 Possible actions:
 
-  details           Output details about the graphs.
-  components        Splits the graph(s) in connected components.
-  remove -n P       Removes at most P% random edges from graph(s).
+  details           Output statistics about the graphs: size, degrees,
+                    density, reciprocity, clustering, components, diameter.
+                    Use -format table, json or csv.
+  degree            Writes the in, out and total degree of every node, and
+                    a histogram of total degrees in bins growing by -base.
+  clustering        Writes the triangles and local clustering coefficient of
+                    every node, with edges followed both ways, and prints
+                    the average and global clustering coefficients. With
+                    -samples N, estimates them from N pairs of neighbours
+                    per node, for huge graphs.
+  distance -pairs F Writes the shortest path distance in the graph(s) of
+                    each pair of nodes in the edge list F, or -1 if there is
+                    no path. Counts edges, or adds up weights with -weighted.
+  kcore [-k K]      Writes the core number of every node, with edges followed
+                    both ways. With -k K, also writes the K-core subgraph.
+  components        Splits the graph(s) in connected components, with edges
+                    followed both ways (like wcc). With -stream, streams the
+                    edges instead of loading the graph, for files too large
+                    for memory; edges then keep all their columns.
+  scc               Splits directed graph(s) in strongly connected components.
+  wcc               Splits directed graph(s) in weakly connected components,
+                    following edges both ways.
+  largest           Writes the largest (weakly) connected component of the
+                    graph(s), relabelled from 1, and its autoincr mapping.
+                    Use -top N for the N largest, or -minsize S for all
+                    components with at least S nodes.
+  remove -n P       Removes at most P% random edges from graph(s), never
+                    the edges of a minimum spanning forest (with -maxst, a
+                    maximum one), so that components stay connected.
+                    With -neg N, also samples N non-edges (see -negmode).
+  remove -folds K   Splits the removable edges into K folds, and writes a
+                    train/test pair of files for each fold, plus a manifest.
+  remove -repeat R -n P
+                    Like remove -n P, but makes R splits, written like -folds.
   force-undirected  Force the graph(s) into undirected graph(s).
 
 All actions except 'details' output in new files whose names are controlled
@@ -67,7 +123,14 @@ func main() {
 
 	controller := map[string]Action{
 		"details":          actionDetails,
+		"degree":           actionDegree,
+		"clustering":       actionClustering,
+		"distance":         actionDistance,
+		"kcore":            actionKCore,
 		"components":       actionComponents,
+		"scc":              actionScc,
+		"wcc":              actionWcc,
+		"largest":          actionLargest,
 		"remove":           actionRemove,
 		"force-undirected": actionForceUndirected,
 	}
@@ -86,153 +149,468 @@ func main() {
 	action()
 }
 
-// Return whether the given graphs are directed on undirected.
+// Details of one graph file, as output by actionDetails.
+type details struct {
+	File string
+	algo.Stats
+	Duplicates int // edges repeated in the file
+}
+
+// Names and values of the details, in output order.
+func (d details) fields() [][2]string {
+	typ, connected := "Undirected", "connected"
+	if d.Directed {
+		typ = "Directed"
+	}
+	if d.Components > 1 {
+		connected = "disconnected"
+	}
+	f := func(x float64) string { return strconv.FormatFloat(x, 'g', 6, 64) }
+	return [][2]string{
+		{"filename", d.File},
+		{"type", typ},
+		{"connected", connected},
+		{"components", strconv.Itoa(d.Components)},
+		{"largest component", strconv.Itoa(d.Largest)},
+		{"nodes", strconv.Itoa(d.Nodes)},
+		{"edges", strconv.Itoa(d.Edges)},
+		{"self-loops", strconv.Itoa(d.SelfLoops)},
+		{"duplicate edges", strconv.Itoa(d.Duplicates)},
+		{"density", f(d.Density)},
+		{"reciprocity", f(d.Reciprocity)},
+		{"min degree", strconv.Itoa(d.MinDegree)},
+		{"max degree", strconv.Itoa(d.MaxDegree)},
+		{"mean degree", f(d.MeanDegree)},
+		{"median degree", f(d.MedianDegree)},
+		{"power law exponent", f(d.PowerLaw)},
+		{"triangles", strconv.Itoa(d.Triangles)},
+		{"clustering coefficient", f(d.Clustering)},
+		{"global clustering", f(d.Transitivity)},
+		{"diameter (estimate)", strconv.Itoa(d.Diameter)},
+	}
+}
+
+// Output statistics about the given graphs, in the -format chosen.
 func actionDetails() {
-	files := flag.Args()
-	fmt.Println("filename\t\tType\t\tConnected?\t#components\t#nodes\t#edges")
-	fmt.Println("========\t\t====\t\t==========\t===========\t======\t======")
-	for _, f := range files {
-		graph, err := ReadGraph(f)
+	var all []details
+	for _, f := range flag.Args() {
+		g, dups, err := graph.ReadDuplicates(f)
 		if err != nil {
-			fmt.Printf("%s \t\t Cannot read graph (%s).\n", f, err.Error())
+			fmt.Printf("%s: Cannot read graph. Skipping. (%s)\n", f, err.Error())
 			continue
 		}
+		all = append(all, details{File: f, Stats: algo.Statistics(g), Duplicates: dups})
+	}
 
-		fmt.Printf("%s\t\t", f)
-		undir := graph.IsUndirected()
-		if undir {
-			fmt.Print("Undirected\t")
-		} else {
-			fmt.Print("Directed\t")
+	var err error
+	switch *flagFormat {
+	case "table":
+		err = writeDetailsTable(os.Stdout, all)
+	case "json":
+		var raw []byte
+		raw, err = json.MarshalIndent(all, "", "  ")
+		if err == nil {
+			_, err = fmt.Println(string(raw))
+		}
+	case "csv":
+		err = writeDetailsCsv(os.Stdout, all)
+	default:
+		err = fmt.Errorf("Unknown -format %q. Use table, json or csv.", *flagFormat)
+	}
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+// Write details as aligned name and value lines, one block per graph.
+func writeDetailsTable(w io.Writer, all []details) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for i, d := range all {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		for _, field := range d.fields() {
+			fmt.Fprintf(tw, "%s\t%s\n", field[0], field[1])
+		}
+	}
+	return tw.Flush()
+}
+
+// Write details as CSV with a header line, one line per graph.
+func writeDetailsCsv(w io.Writer, all []details) error {
+	cw := csv.NewWriter(w)
+	for i, d := range all {
+		fields := d.fields()
+		if i == 0 {
+			header := make([]string, len(fields))
+			for j, field := range fields {
+				header[j] = field[0]
+			}
+			cw.Write(header)
+		}
+		row := make([]string, len(fields))
+		for j, field := range fields {
+			row[j] = field[1]
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Writes the degrees of the nodes of graphs to degrees_<file>.csv, and
+// their log-binned histogram to histogram_<file>.csv, both prefixed with -o.
+func actionDegree() {
+	if !(*flagBase > 1) || math.IsInf(*flagBase, 1) {
+		fmt.Println("-base must be finite and more than 1.")
+		return
+	}
+	for _, f := range flag.Args() {
+		g, err := algo.ReadGraph(f)
+		if err != nil {
+			fmt.Printf("%s: Cannot read graph. Skipping. (%s)\n", f, err.Error())
+			continue
+		}
+
+		degrees := algo.Degrees(g.View())
+		if err := writeDegrees(degrees, *flagOutput+"degrees_"+f+".csv"); err != nil {
+			fmt.Printf("%s: %s\n", f, err.Error())
+		}
+		if err := writeHistogram(degrees, *flagOutput+"histogram_"+f+".csv"); err != nil {
+			fmt.Printf("%s: %s\n", f, err.Error())
+		}
+	}
+}
+
+// Write one line of id, in, out and total degree per node, with a header.
+func writeDegrees(degrees []algo.Degree, fname string) error {
+	rows := [][]string{{"id", "in", "out", "total"}}
+	for _, d := range degrees {
+		rows = append(rows, []string{
+			strconv.Itoa(d.Id),
+			strconv.Itoa(d.In),
+			strconv.Itoa(d.Out),
+			strconv.Itoa(d.Total),
+		})
+	}
+	return writeCsv(rows, fname)
+}
+
+// Write the histogram of total degrees: the bounds of each bin (high
+// excluded), its count, and its count per unit of width over all nodes.
+func writeHistogram(degrees []algo.Degree, fname string) error {
+	totals := make([]int, len(degrees))
+	for i, d := range degrees {
+		totals[i] = d.Total
+	}
+	bins, err := algo.LogHistogram(totals, *flagBase)
+	if err != nil {
+		return err
+	}
+	rows := [][]string{{"low", "high", "count", "density"}}
+	for _, b := range bins {
+		rows = append(rows, []string{
+			strconv.Itoa(b.Low),
+			strconv.Itoa(b.High),
+			strconv.Itoa(b.Count),
+			strconv.FormatFloat(b.Density(len(totals)), 'g', -1, 64),
+		})
+	}
+	return writeCsv(rows, fname)
+}
+
+// Write rows to a new CSV file.
+func writeCsv(rows [][]string, fname string) error {
+	file, err := os.Create(fname)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(file)
+	w.WriteAll(rows)
+	if err := w.Error(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Writes the triangles and local clustering coefficient of the nodes of
+// graphs to clustering_<file>.csv, prefixed with -o, and prints the totals.
+func actionClustering() {
+	rnd := rand.New(rand.NewSource(*flagSeed))
+	for _, f := range flag.Args() {
+		g, err := graph.Read(f)
+		if err != nil {
+			fmt.Printf("%s: Cannot read graph. Skipping. (%s)\n", f, err.Error())
+			continue
 		}
 
-		components := graph.ConnectedGraphs()
-		if len(components) <= 1 {
-			fmt.Print("connected\t")
+		u := graph.Undirected(g)
+		var c algo.Clustering
+		if *flagSamples > 0 {
+			c = algo.ApproximateClustering(u, *flagSamples, rnd)
 		} else {
-			fmt.Print("disconnected\t")
+			c = algo.ExactClustering(u, *flagWorkers)
+		}
+
+		rows := [][]string{{"id", "triangles", "clustering"}}
+		for i, id := range u.Nodes() {
+			rows = append(rows, []string{
+				strconv.Itoa(id),
+				strconv.Itoa(c.Triangles[i]),
+				strconv.FormatFloat(c.Local[i], 'g', -1, 64),
+			})
+		}
+		if err := writeCsv(rows, *flagOutput+"clustering_"+f+".csv"); err != nil {
+			fmt.Printf("%s: %s\n", f, err.Error())
+			continue
 		}
+		fmt.Printf("%s: %d triangles, average clustering %g, global clustering %g.\n", f, c.Total, c.Average, c.Global)
+	}
+}
 
-		fmt.Printf("%d\t\t", len(components))
-		fmt.Printf("%d\t", len(graph.Nodes))
+// Writes the distance between the nodes of every pair in -pairs, in each
+// graph, to distances_<file>: the pair, then the distance.
+func actionDistance() {
+	if *flagPairs == "" {
+		fmt.Println("Need a -pairs file for distance.")
+		return
+	}
+	pairs, err := readPairs(*flagPairs)
+	if err != nil {
+		fmt.Printf("%s: Cannot read pairs. (%s)\n", *flagPairs, err.Error())
+		return
+	}
 
-		var noEdges int
-		edges := graph.EdgeList()
-		if undir {
-			noEdges = len(edges) / 2
+	read := graph.Read
+	if *flagWeights {
+		read = graph.ReadWeighted
+	}
+	for _, f := range flag.Args() {
+		g, err := read(f)
+		if err != nil {
+			fmt.Printf("%s: Cannot read graph. Skipping. (%s)\n", f, err.Error())
+			continue
+		}
+		if err := writeDistances(g, pairs, *flagOutput+"distances_"+f); err != nil {
+			fmt.Printf("%s: %s\n", f, err.Error())
+		}
+	}
+}
+
+// Read the pairs of nodes of an edge list. Other columns are ignored.
+func readPairs(fname string) ([]*algo.Edge, error) {
+	reader, err := util.OpenEdges(fname)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	var pairs []*algo.Edge
+	for {
+		from, to, _, err := reader.Read()
+		if err == io.EOF {
+			return pairs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, &algo.Edge{From: from, To: to})
+	}
+}
+
+// Write each pair with its distance in g, or -1 if there is no path.
+func writeDistances(g graph.Graph, pairs []*algo.Edge, fname string) error {
+	writer, err := util.CreateEdges(fname)
+	if err != nil {
+		return err
+	}
+	for _, p := range pairs {
+		var dist float64
+		if *flagWeights {
+			dist = algo.WeightedDistance(g, p.From, p.To)
 		} else {
-			noEdges = len(edges)
+			dist = float64(algo.HopDistance(g, p.From, p.To))
+		}
+		if math.IsInf(dist, 1) {
+			dist = -1
 		}
+		if err := writer.WriteWeighted(p.From, p.To, dist, nil); err != nil {
+			writer.Close()
+			return err
+		}
+	}
+	return writer.Close()
+}
 
-		fmt.Printf("%d\n", noEdges)
+// Writes the core numbers of the nodes of graphs to cores_<file>.csv, and
+// with -k, the k-core to kcore_<file>, both prefixed with -o.
+func actionKCore() {
+	for _, f := range flag.Args() {
+		g, err := graph.Read(f)
+		if err != nil {
+			fmt.Printf("%s: Cannot read graph. Skipping. (%s)\n", f, err.Error())
+			continue
+		}
+
+		u := graph.Undirected(g)
+		ids, cores := u.Nodes(), algo.CoreNumbers(u)
+		rows := [][]string{{"id", "core"}}
+		var core []int
+		for i, id := range ids {
+			rows = append(rows, []string{strconv.Itoa(id), strconv.Itoa(cores[i])})
+			if cores[i] >= *flagK {
+				core = append(core, id)
+			}
+		}
+		if err := writeCsv(rows, *flagOutput+"cores_"+f+".csv"); err != nil {
+			fmt.Printf("%s: %s\n", f, err.Error())
+		}
+
+		if *flagK > 0 {
+			if err := writeGraph(graph.Induced(g, core), *flagOutput+"kcore_"+f); err != nil {
+				fmt.Printf("%s: %s\n", f, err.Error())
+			}
+		}
 	}
 }
 
 // Splits graphs into their connected compoments, and writes those components as separate files.
 func actionComponents() {
+	if !*flagStream {
+		splitComponents(algo.WeaklyConnected)
+		return
+	}
+	for _, f := range flag.Args() {
+		name := func(i int) string { return *flagOutput + strconv.Itoa(i) + "_" + f }
+		if err := algo.SplitComponents(f, name); err != nil {
+			fmt.Printf("%s: %s\n", f, err.Error())
+		}
+	}
+}
+
+// Splits graphs into their strongly connected components, written like in actionComponents.
+func actionScc() {
+	splitComponents(algo.StronglyConnected)
+}
+
+// Splits graphs into their weakly connected components, written like in actionComponents.
+func actionWcc() {
+	splitComponents(algo.WeaklyConnected)
+}
+
+// Read every graph, and write each component found by find to a separate
+// file: the output prefix, the component number, an underscore and the file name.
+func splitComponents(find func(graph.Graph) [][]int) {
 	files := flag.Args()
 	for _, f := range files {
-		graph, err := ReadGraph(f)
+		g, err := graph.Read(f)
 		if err != nil {
 			fmt.Printf("%s: Cannot read graph. Skipping. (%s)\n", f, err.Error())
 			continue
 		}
 
-		components := graph.ConnectedGraphs()
-
-		for i, comp := range components {
+		for i, comp := range find(g) {
 			fname := *flagOutput + strconv.Itoa(i) + "_" + f
-			wr, err := os.Create(fname)
+			err = writeGraph(graph.Induced(g, comp), fname)
 			if err != nil {
-				fmt.Printf("%s: Cannot write to %s, skipping connected component #%d. (%s)\n", f, fname, i, err.Error())
+				fmt.Printf("%s: Skipping connected component #%d. %s\n", f, i, err.Error())
 			}
-			writer := util.NewWriter(wr)
-			comp.EachEdge(func(from, to *Node) bool {
-				err := writer.Write(from.Id, to.Id, nil)
-				if err != nil {
-					fmt.Printf("%s: Cannot write edge to %s, skipping remaining of connected component #%d. (%s)\n", f, fname, i, err.Error())
-					return false
-				}
-				return true
-			})
-			err = writer.Flush()
-			if err != nil {
-				fmt.Printf("%s: (FLUSH) Output might be corrupted.")
+		}
+	}
+}
+
+// Writes the largest weakly connected components of graphs, with their nodes
+// relabelled from 1. Each component is written like in actionComponents, the
+// largest first, and its mapping to the same file name followed by .json.
+// Revert with autoincr -action revert -index <mapping>.
+func actionLargest() {
+	files := flag.Args()
+	for _, f := range files {
+		g, err := graph.Read(f)
+		if err != nil {
+			fmt.Printf("%s: Cannot read graph. Skipping. (%s)\n", f, err.Error())
+			continue
+		}
+
+		comps := algo.WeaklyConnected(g)
+		algo.BySize(comps)
+		for i, comp := range comps {
+			if *flagMinSize > 0 && len(comp) < *flagMinSize {
+				break
+			}
+			if *flagMinSize <= 0 && i >= *flagTop {
+				break
+			}
+
+			fname := *flagOutput + strconv.Itoa(i) + "_" + f
+			relabelled, ids := graph.Relabel(graph.Induced(g, comp))
+			err = writeGraph(relabelled, fname)
+			if err == nil {
+				err = util.WriteMapping(ids, fname+".json")
 			}
-			err = wr.Close()
 			if err != nil {
-				fmt.Printf("%s: (CLOSE) Output might be corrupted.")
+				fmt.Printf("%s: Skipping connected component #%d. %s\n", f, i, err.Error())
 			}
 		}
 	}
 }
 
 // Get (perhaps directed) graphs and make them directed by forcing all edges to be bi-directional.
+// Added reverse edges get the weight of their edge.
 func actionForceUndirected() {
 	files := flag.Args()
 	for _, f := range files {
-		graph, err := ReadGraph(f)
+		g, err := graph.Read(f)
 		if err != nil {
 			fmt.Printf("%s: Cannot read graph. Skipping. (%s)\n", f, err.Error())
 			continue
 		}
 
-		fname := *flagOutput + f
-		wr, err := os.Create(fname)
+		err = writeGraph(graph.Undirected(g), *flagOutput+f)
 		if err != nil {
-			fmt.Printf("%s: Cannot write to %s, skipping file. (%s)\n", f, fname, err.Error())
-			continue
-		}
-		writer := util.NewWriter(wr)
-		edges := graph.EdgeList()
-		for _, e := range edges {
-			// it has from -> to if we got here.
-			// adding to -> from.
-			graph.AddDirectedEdge(e.To, e.From)
-		}
-		graph.EachEdge(func(from, to *Node) bool {
-			err := writer.Write(from.Id, to.Id, nil)
-			if err != nil {
-				fmt.Printf("%s: Cannot write edge to %s, aborting this graph. (%s)\n", f, fname, err.Error())
-				return false
-			}
-			return true
-		})
-		err = writer.Flush()
-		if err != nil {
-			fmt.Printf("%s: (FLUSH) Output might be corrupted.", f)
-		}
-		err = wr.Close()
-		if err != nil {
-			fmt.Printf("%s: (CLOSE) Output might be corrupted.", f)
+			fmt.Printf("%s: %s\n", f, err.Error())
 		}
 	}
 }
 
-// write a graph
-func writeGraph(graph *Graph, fname string) error {
-	wr, err := os.Create(fname)
+// Write the edge from -> to, with its weight in g when weighted.
+func writeEdge(writer util.EdgeWriter, g graph.Weighted, weighted bool, from, to int) error {
+	if weighted {
+		return writer.WriteWeighted(from, to, g.Weight(from, to), nil)
+	}
+	return writer.Write(from, to, nil)
+}
+
+// write a graph, with a weight column if it has weights
+func writeGraph(g graph.Graph, fname string) error {
+	if err := graph.Write(g, fname); err != nil {
+		return fmt.Errorf("Cannot write %s, skipping file. (%s)", fname, err.Error())
+	}
+	return nil
+}
+
+// Write a list of edges to fname. With all, also write the reverse of each edge.
+// Edge weights are taken from g, if it is weighted; g can be nil.
+func writeEdges(g *algo.Graph, edges []*algo.Edge, fname string, all bool) error {
+	writer, err := util.CreateEdges(fname)
 	if err != nil {
 		return fmt.Errorf("Cannot write to %s, skipping file. (%s)", fname, err.Error())
 	}
-	writer := util.NewWriter(wr)
-	err = nil
-	graph.EachEdge(func(from, to *Node) bool {
-		err := writer.Write(from.Id, to.Id, nil)
+	weighted := g != nil && g.IsWeighted()
+	for _, edge := range edges {
+		err = writeEdge(writer, g, weighted, edge.From, edge.To)
 		if err != nil {
-			err = fmt.Errorf("Cannot write edge to %s, aborting this graph. (%s)", fname, err.Error())
-			return false
+			return fmt.Errorf("Cannot write edge to %s. Skipping remaining of graph. (%s)", fname, err.Error())
+		}
+		if all {
+			err = writeEdge(writer, g, weighted, edge.To, edge.From)
+			if err != nil {
+				return fmt.Errorf("Cannot write (reverse) edge to %s. Skipping remaining of graph. (%s)", fname, err.Error())
+			}
 		}
-		return true
-	})
-	if err != nil {
-		return err
-	}
-	err = writer.Flush()
-	if err != nil {
-		return fmt.Errorf("(FLUSH) Output might be corrupted. (%s)", err.Error())
 	}
-	err = wr.Close()
+
+	err = writer.Close()
 	if err != nil {
 		return fmt.Errorf("(CLOSE) Output might be corrupted. (%s)", err.Error())
 	}
@@ -241,68 +619,153 @@ func writeGraph(graph *Graph, fname string) error {
 
 // Remove random edges from a graph using a spanning tree to assure connectness.
 func actionRemove() {
+	mode, err := algo.ParseSampleMode(*flagNegMode)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if *flagRepeat > 0 && *flagN <= 0 {
+		fmt.Println("-repeat needs a positive -n, the % of edges removed by each split.")
+		return
+	}
+	rnd := rand.New(rand.NewSource(*flagSeed))
+	read := algo.ReadGraph
+	if *flagMaxSt {
+		read = algo.ReadWeightedGraph
+	}
+
 	files := flag.Args()
 	for _, f := range files {
-		graph, err := ReadGraph(f)
+		g, err := read(f)
 		if err != nil {
 			fmt.Printf("%s: Cannot read graph. Skipping. (%s)\n", f, err.Error())
 			continue
 		}
 
-		if !*flagForce && !graph.IsConnected() {
-			fmt.Printf("%s: Graph not connected. Skipping. Use the --force to do it anyway.\n", f)
+		if !*flagForce && !g.IsUndirected() {
+			fmt.Printf("%s: Graph is directed. Skipping. Use the --force to do it anyway.\n", f)
 			continue
 		}
 
-		if !*flagForce && !graph.IsUndirected() {
-			fmt.Printf("%s: Graph is directed. Skipping. Use the --force to do it anyway.\n", f)
-			continue
+		// sample before removing, so that removed edges are not non-edges
+		var nonEdges []*algo.Edge
+		if *flagNeg > 0 {
+			nonEdges = g.SampleNonEdges(*flagNeg, mode, *flagHops, rnd)
+			if len(nonEdges) < *flagNeg {
+				fmt.Printf("%s: Only found %d non-edges.\n", f, len(nonEdges))
+			}
 		}
 
-		edges := len(graph.EdgeList())
+		edges := len(g.EdgeList())
 		remove := int(math.Floor(*flagN*float64(edges)/2 + 0.5))
-		mst := graph.Mst()
-		removed := graph.RemoveRandomEdges(remove, mst)
+		// the edges of a spanning forest are never removed, so that every
+		// component stays connected
+		mst := g.Mst()
+		if *flagMaxSt {
+			mst = g.MaxSt()
+		}
+
+		if *flagFolds > 0 || *flagRepeat > 0 {
+			err = writeSplits(g, f, mst, remove, mode, rnd)
+			if err != nil {
+				fmt.Printf("%s: %s\n", f, err.Error())
+			}
+			continue
+		}
+
+		// removed edges lose their weights, so they are written with those of g
+		rest := g.Copy()
+		removed := rest.RemoveRandomEdgesRand(remove, mst, rnd)
 
 		// write out the processed graph
 		fname := *flagOutput + f
-		err = writeGraph(graph, fname)
+		err = writeGraph(rest.View(), fname)
 		if err != nil {
-			fmt.Printf("%s: %s\n", err.Error())
+			fmt.Printf("%s: %s\n", f, err.Error())
 			continue
 		}
 
-		fname = *flagOutput + "removed_" + f
-		wr, err := os.Create(fname)
+		err = writeEdges(g, removed, *flagOutput+"removed_"+f, *flagAll)
 		if err != nil {
-			fmt.Printf("%s: Cannot write to %s, skipping file. (%s)", f, fname, err.Error())
+			fmt.Printf("%s: %s\n", f, err.Error())
 			continue
 		}
 
-		writer := util.NewWriter(wr)
-		for _, edge := range removed {
-			err = writer.Write(edge.From, edge.To, nil)
+		if *flagNeg > 0 {
+			err = writeEdges(nil, nonEdges, *flagOutput+"nonedges_"+f, *flagAll)
 			if err != nil {
-				fmt.Printf("%s: Cannot write edge to %s. Skipping remaining of graph. (%s)", f, fname, err.Error())
-				break
+				fmt.Printf("%s: %s\n", f, err.Error())
 			}
-			if *flagAll {
-				err = writer.Write(edge.To, edge.From, nil)
-				if err != nil {
-					fmt.Printf("%s: Cannot write (reverse) edge to %s. Skipping remaining of graph. (%s)", f, fname, err.Error())
-					break
-				}
+		}
+	}
+}
+
+// One train/test split, as listed in the manifest.
+type split struct {
+	Train    string
+	Test     string
+	NonEdges string `json:",omitempty"`
+	Removed  int
+}
+
+// The manifest of the splits made from one input graph.
+type manifest struct {
+	Input    string
+	Seed     int64
+	Folds    int     `json:",omitempty"`
+	Repeats  int     `json:",omitempty"`
+	Fraction float64 `json:",omitempty"`
+	Splits   []split
+}
+
+// Make the -folds or -repeat splits of g (read from file f), and write
+// them along with a manifest. Edges in mst are never removed.
+func writeSplits(g *algo.Graph, f string, mst algo.Mst, remove int, mode algo.SampleMode, rnd *rand.Rand) error {
+	man := manifest{Input: f, Seed: *flagSeed}
+
+	var tests [][]*algo.Edge
+	if *flagFolds > 0 {
+		man.Folds = *flagFolds
+		tests = algo.Folds(g.RemovableEdges(mst), *flagFolds, rnd)
+	} else {
+		man.Repeats = *flagRepeat
+		man.Fraction = *flagN
+		for i := 0; i < *flagRepeat; i++ {
+			tests = append(tests, g.Copy().RemoveRandomEdgesRand(remove, mst, rnd))
+		}
+	}
+
+	for i, test := range tests {
+		prefix := *flagOutput + strconv.Itoa(i) + "_"
+		sp := split{
+			Train:   prefix + "train_" + f,
+			Test:    prefix + "test_" + f,
+			Removed: len(test),
+		}
+
+		if *flagNeg > 0 {
+			sp.NonEdges = prefix + "nonedges_" + f
+			err := writeEdges(nil, g.SampleNonEdges(*flagNeg, mode, *flagHops, rnd), sp.NonEdges, *flagAll)
+			if err != nil {
+				return err
 			}
 		}
 
-		err = writer.Flush()
-		if err != nil {
-			fmt.Printf("%s: (FLUSH) Output might be corrupted.", f)
+		train := g.Copy()
+		train.RemoveEdges(test)
+		if err := writeGraph(train.View(), sp.Train); err != nil {
+			return err
 		}
-		err = wr.Close()
-		if err != nil {
-			fmt.Printf("%s: (CLOSE) Output might be corrupted.", f)
+		if err := writeEdges(g, test, sp.Test, *flagAll); err != nil {
+			return err
 		}
+		man.Splits = append(man.Splits, sp)
+	}
 
+	fname := *flagOutput + "manifest_" + f + ".json"
+	raw, err := json.MarshalIndent(man, "", "  ")
+	if err != nil {
+		return err
 	}
+	return ioutil.WriteFile(fname, raw, 0644)
 }
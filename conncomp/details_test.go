@@ -0,0 +1,27 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/vladvelici/graph-dataset-tools/algo"
+)
+
+func TestWriteDetailsCsv(t *testing.T) {
+	all := []details{
+		{File: "a.csv", Stats: algo.Stats{Nodes: 3, Edges: 2, Components: 1}},
+		{File: "b.csv", Stats: algo.Stats{Nodes: 4, Directed: true, Components: 2}, Duplicates: 5},
+	}
+	var buf bytes.Buffer
+	if err := writeDetailsCsv(&buf, all); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "filename,type,connected,") {
+		t.Fatalf("Expected a header and 2 lines, got %q.", buf.String())
+	}
+	if !strings.HasPrefix(lines[2], "b.csv,Directed,disconnected,2,0,4,0,0,5,") {
+		t.Errorf("Unexpected line %q.", lines[2])
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vladvelici/graph-dataset-tools/algo"
+)
+
+func TestWriteWeightedGraph(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conncomp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in.csv")
+	if err := ioutil.WriteFile(in, []byte("1,2,0.5\n2,1,0.5\n2,3,3\n3,2,3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := algo.ReadGraph(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "out.csv")
+	if err := writeGraph(g.View(), out); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "1,2,0.5\n2,1,0.5\n2,3,3\n3,2,3\n"
+	if string(data) != expected {
+		t.Errorf("Expected\n%s\ngot\n%s", expected, data)
+	}
+}
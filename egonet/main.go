@@ -0,0 +1,138 @@
+/*
+Extracts ego networks: for every seed node (the ego), the subgraph induced
+by the nodes at most k edges away from it. In directed graphs edges are
+followed forwards only.
+
+Inputs can be in any format util.OpenEdges reads. Outputs are written in the
+format of their extension, see util.CreateEdges: .txt files are written as
+CSV. Weights are kept.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+
+	"github.com/vladvelici/graph-dataset-tools/algo"
+	"github.com/vladvelici/graph-dataset-tools/graph"
+	"github.com/vladvelici/graph-dataset-tools/util"
+)
+
+var (
+	flagOutput  = flag.String("o", "ego_", "Output file prefix. It will be followed by the ego node ID and an underscore.")
+	flagK       = flag.Int("k", 1, "Number of hops from the ego to include.")
+	flagSeeds   = flag.String("seeds", "", "File with the IDs of the ego nodes, separated by spaces, commas or new lines.")
+	flagEgo     = flag.String("ego", "", "Comma separated IDs of ego nodes, in addition to -seeds.")
+	flagNoEgo   = flag.Bool("noego", false, "Leave the ego node itself out of its network.")
+	flagRelabel = flag.Bool("relabel", false, "Number the nodes of each network from 1, and write the mapping to the output file name followed by .json.")
+	flagHelp    = flag.Bool("help", false, "Show this help message")
+	flagH       = flag.Bool("h", false, "Show this help message")
+)
+
+var helpMessage = `egonet extracts the k-hop ego networks of seed nodes.
+
+Usage:
+
+egonet -seeds <file> [-ego 1,2,...] [flags] <list of edge lists>
+
+For each edge list <file> and each ego, writes the network of the ego to
+<o><ego>_<file>. With -relabel, the mapping from new to original IDs is
+written to <o><ego>_<file>.json, in the format of autoincr index files, so
+that autoincr -action revert can restore the original IDs.
+
+Full list of flags:
+
+`
+
+func help() {
+	fmt.Println(helpMessage)
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = help
+	flag.Parse()
+
+	if *flagHelp || *flagH {
+		help()
+		return
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		fmt.Println("Need at least one input graph file.")
+		return
+	}
+	if *flagK < 0 {
+		fmt.Println("-k cannot be negative.")
+		return
+	}
+	egos, err := readSeeds(*flagSeeds, *flagEgo)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(egos) == 0 {
+		fmt.Println("Need at least one ego node. See -seeds and -ego.")
+		return
+	}
+
+	for _, f := range files {
+		g, err := graph.Read(f)
+		if err != nil {
+			fmt.Printf("%s: Cannot read graph. Skipping. (%s)\n", f, err.Error())
+			continue
+		}
+		nodes := g.Nodes()
+		for _, ego := range egos {
+			if i := sort.SearchInts(nodes, ego); i == len(nodes) || nodes[i] != ego {
+				fmt.Printf("%s: Node %d not in graph. Skipping.\n", f, ego)
+				continue
+			}
+			fname := *flagOutput + strconv.Itoa(ego) + "_" + f
+			if err := writeEgonet(g, ego, fname); err != nil {
+				fmt.Printf("%s: ego %d: %s\n", f, ego, err.Error())
+			}
+		}
+	}
+}
+
+// Read the ego IDs from the file seeds, if any, and the comma separated list
+// ego. Repeated IDs are kept once.
+func readSeeds(seeds, ego string) ([]int, error) {
+	text := ego
+	if seeds != "" {
+		raw, err := ioutil.ReadFile(seeds)
+		if err != nil {
+			return nil, err
+		}
+		text += "\n" + string(raw)
+	}
+	return util.ParseNodeIds(text)
+}
+
+// Write the network of ego in g to fname.
+func writeEgonet(g graph.Graph, ego int, fname string) error {
+	ids := algo.Neighbourhood(g, ego, *flagK)
+	if *flagNoEgo {
+		for i, id := range ids {
+			if id == ego {
+				ids = append(ids[:i], ids[i+1:]...)
+				break
+			}
+		}
+	}
+	net := graph.Graph(graph.Induced(g, ids))
+
+	if !*flagRelabel {
+		return graph.Write(net, fname)
+	}
+	relabelled, ids := graph.Relabel(net)
+	if err := graph.Write(relabelled, fname); err != nil {
+		return err
+	}
+	return util.WriteMapping(ids, fname+".json")
+}
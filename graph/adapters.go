@@ -0,0 +1,129 @@
+package graph
+
+import "sort"
+
+// Copy returns g as a CSR, weights included. Returns g itself if it already
+// is a CSR.
+func Copy(g Graph) *CSR {
+	if c, ok := g.(*CSR); ok {
+		return c
+	}
+	b := NewBuilder()
+	for _, id := range g.Nodes() {
+		b.AddNode(id)
+		for _, to := range g.Neighbours(id) {
+			b.AddWeightedDirectedEdge(id, to, WeightOf(g, id, to))
+		}
+	}
+	return b.Build()
+}
+
+// Undirected returns g with the reverse of every edge added. An edge and its
+// reverse keep their own weights; a reverse edge that was missing gets the
+// weight of its edge. Returns g as it is if it is already undirected.
+func Undirected(g Graph) Graph {
+	if !g.Directed() {
+		return g
+	}
+	b := NewBuilder()
+	for _, id := range g.Nodes() {
+		b.AddNode(id)
+		for _, to := range g.Neighbours(id) {
+			w := WeightOf(g, id, to)
+			b.AddWeightedDirectedEdge(id, to, w)
+			if !g.HasEdge(to, id) {
+				b.AddWeightedDirectedEdge(to, id, w)
+			}
+		}
+	}
+	return b.Build()
+}
+
+// Relabel returns a copy of g with its nodes numbered 1, 2, ... in the order
+// of their IDs, weights included, and the original IDs: ids[i] is the
+// original ID of node i+1.
+func Relabel(g Graph) (c *CSR, ids []int) {
+	ids = g.Nodes()
+	label := make(map[int]int, len(ids))
+	for i, id := range ids {
+		label[id] = i + 1
+	}
+	b := NewBuilder()
+	for _, id := range ids {
+		b.AddNode(label[id])
+		for _, to := range g.Neighbours(id) {
+			b.AddWeightedDirectedEdge(label[id], label[to], WeightOf(g, id, to))
+		}
+	}
+	return b.Build(), ids
+}
+
+// Subgraph is the subgraph of a graph induced by a set of its nodes: those
+// nodes and the edges between them. It is a view, computed on the fly.
+type Subgraph struct {
+	g    Graph
+	ids  []int
+	keep map[int]bool
+}
+
+// Induced returns the subgraph of g induced by the nodes ids. IDs not in g
+// are ignored.
+func Induced(g Graph, ids []int) *Subgraph {
+	nodes := g.Nodes()
+	s := &Subgraph{g: g, keep: make(map[int]bool, len(ids))}
+	for _, id := range ids {
+		if s.keep[id] {
+			continue
+		}
+		if i := sort.SearchInts(nodes, id); i < len(nodes) && nodes[i] == id {
+			s.keep[id] = true
+			s.ids = append(s.ids, id)
+		}
+	}
+	sort.Ints(s.ids)
+	return s
+}
+
+// Nodes returns the IDs of the nodes of the subgraph, in ascending order.
+func (s *Subgraph) Nodes() []int {
+	return s.ids
+}
+
+// Neighbours returns the neighbours of id that are in the subgraph.
+func (s *Subgraph) Neighbours(id int) []int {
+	if !s.keep[id] {
+		return nil
+	}
+	var res []int
+	for _, to := range s.g.Neighbours(id) {
+		if s.keep[to] {
+			res = append(res, to)
+		}
+	}
+	return res
+}
+
+// Degree returns the number of edges from id in the subgraph.
+func (s *Subgraph) Degree(id int) int {
+	return len(s.Neighbours(id))
+}
+
+// HasEdge returns whether the edge from -> to is in the subgraph.
+func (s *Subgraph) HasEdge(from, to int) bool {
+	return s.keep[from] && s.keep[to] && s.g.HasEdge(from, to)
+}
+
+// Directed returns whether some edge of the subgraph has no reverse edge.
+func (s *Subgraph) Directed() bool {
+	return s.g.Directed() && IsDirected(s)
+}
+
+// Weight returns the weight of the edge from -> to in the original graph.
+func (s *Subgraph) Weight(from, to int) float64 {
+	return WeightOf(s.g, from, to)
+}
+
+// IsWeighted returns whether the original graph has weights.
+func (s *Subgraph) IsWeighted() bool {
+	return IsWeighted(s.g)
+}
@@ -0,0 +1,254 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/vladvelici/graph-dataset-tools/util"
+)
+
+// CSR is an immutable graph in compressed sparse row form: the neighbours
+// of every node sit next to each other in one array, so it needs a few bytes
+// per edge. It implements Graph and Weighted.
+type CSR struct {
+	ids      []int     // node IDs, sorted
+	offsets  []int     // neighbours of ids[i] are nbrs[offsets[i]:offsets[i+1]]
+	nbrs     []int     // neighbour IDs, sorted within each row
+	weights  []float64 // weights of the edges in nbrs, nil if all are 1
+	directed bool
+}
+
+// Index of the node id in g.ids, or -1.
+func (g *CSR) index(id int) int {
+	i := sort.SearchInts(g.ids, id)
+	if i < len(g.ids) && g.ids[i] == id {
+		return i
+	}
+	return -1
+}
+
+// Nodes returns the IDs of all nodes, in ascending order.
+func (g *CSR) Nodes() []int {
+	return g.ids
+}
+
+// Neighbours returns the IDs of the neighbours of id, in ascending order.
+func (g *CSR) Neighbours(id int) []int {
+	i := g.index(id)
+	if i < 0 {
+		return nil
+	}
+	return g.nbrs[g.offsets[i]:g.offsets[i+1]]
+}
+
+// Degree returns the number of edges from id.
+func (g *CSR) Degree(id int) int {
+	return len(g.Neighbours(id))
+}
+
+// Position of the edge from -> to in nbrs, or -1.
+func (g *CSR) edge(from, to int) int {
+	i := g.index(from)
+	if i < 0 {
+		return -1
+	}
+	row := g.nbrs[g.offsets[i]:g.offsets[i+1]]
+	p := sort.SearchInts(row, to)
+	if p < len(row) && row[p] == to {
+		return g.offsets[i] + p
+	}
+	return -1
+}
+
+// HasEdge returns whether there is an edge from -> to.
+func (g *CSR) HasEdge(from, to int) bool {
+	return g.edge(from, to) >= 0
+}
+
+// Weight of the edge from -> to, 1 if it has none or does not exist.
+func (g *CSR) Weight(from, to int) float64 {
+	if g.weights == nil {
+		return 1
+	}
+	if p := g.edge(from, to); p >= 0 {
+		return g.weights[p]
+	}
+	return 1
+}
+
+// IsWeighted returns whether any edge has a weight other than 1.
+func (g *CSR) IsWeighted() bool {
+	return g.weights != nil
+}
+
+// Directed returns whether some edge has no reverse edge.
+func (g *CSR) Directed() bool {
+	return g.directed
+}
+
+// NumEdges returns the number of (directed) edges.
+func (g *CSR) NumEdges() int {
+	return len(g.nbrs)
+}
+
+// Builder collects edges for a CSR. It builds a util.BinGraph first, so
+// both formats treat repeated edges the same way.
+type Builder struct {
+	b *util.BinGraphBuilder
+}
+
+// NewBuilder creates an empty builder.
+func NewBuilder() *Builder {
+	return &Builder{util.NewBinGraphBuilder()}
+}
+
+// AddNode adds a node, even if it has no edges.
+func (b *Builder) AddNode(id int) {
+	b.b.AddNode(id)
+}
+
+// AddDirectedEdge adds the edge from -> to, with weight 1.
+func (b *Builder) AddDirectedEdge(from, to int) {
+	b.AddWeightedDirectedEdge(from, to, 1)
+}
+
+// AddEdge adds an undirected edge: from -> to and to -> from.
+func (b *Builder) AddEdge(from, to int) {
+	b.AddWeightedEdge(from, to, 1)
+}
+
+// AddWeightedEdge adds an undirected edge with a weight.
+func (b *Builder) AddWeightedEdge(from, to int, w float64) {
+	b.AddWeightedDirectedEdge(from, to, w)
+	b.AddWeightedDirectedEdge(to, from, w)
+}
+
+// AddWeightedDirectedEdge adds the edge from -> to with a weight.
+func (b *Builder) AddWeightedDirectedEdge(from, to int, w float64) {
+	b.b.Add(from, to, w)
+}
+
+// Build sorts the edges into a CSR. Repeated edges are kept once, with the
+// weight of the last one added, like in util.BinGraphBuilder. Panics with
+// more nodes than the binary graph format can number (2^32), which graphs
+// held in memory do not reach; Read returns that as an error instead.
+func (b *Builder) Build() *CSR {
+	bg, err := b.b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return FromBin(bg)
+}
+
+// FromBin copies a binary graph file into a CSR.
+func FromBin(bg *util.BinGraph) *CSR {
+	g := &CSR{
+		ids:     make([]int, bg.NumNodes()),
+		offsets: make([]int, bg.NumNodes()+1),
+		nbrs:    make([]int, bg.NumEdges()),
+	}
+	for i, id := range bg.Ids {
+		g.ids[i] = int(id)
+		g.offsets[i+1] = int(bg.Offsets[i+1])
+	}
+	// node IDs are sorted, so rows of IDs stay sorted
+	for p, nb := range bg.Neighbours {
+		g.nbrs[p] = g.ids[nb]
+	}
+	if bg.Weights != nil {
+		g.weights = make([]float64, len(bg.Weights))
+		copy(g.weights, bg.Weights)
+	}
+	g.directed = IsDirected(g)
+	return g
+}
+
+// Read reads the graph in the file at path, which is a binary graph file or
+// an edge list in any format of util.OpenEdges. A third column, if any, is
+// read as the edge weight when it is a number.
+func Read(path string) (*CSR, error) {
+	g, _, err := read(path, false)
+	return g, err
+}
+
+// ReadWeighted is like Read, for callers that need the weights: a third
+// column that is not a number is an error.
+func ReadWeighted(path string) (*CSR, error) {
+	g, _, err := read(path, true)
+	return g, err
+}
+
+// ReadDuplicates is like Read, and also returns how many edges of the file
+// repeat an earlier edge. Binary graph files have none.
+func ReadDuplicates(path string) (*CSR, int, error) {
+	return read(path, false)
+}
+
+func read(path string, weighted bool) (*CSR, int, error) {
+	bin, err := util.IsBinGraph(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if bin {
+		bg, err := util.OpenBinGraph(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer bg.Close()
+		return FromBin(bg), 0, nil
+	}
+
+	reader, err := util.OpenEdges(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer reader.Close()
+
+	readEdge := reader.ReadWeighted
+	if weighted {
+		readEdge = func() (int, int, float64, []string, error) { return util.ReadWeights(reader) }
+	}
+	b := NewBuilder()
+	for {
+		from, to, w, _, err := readEdge()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: %s", path, err.Error())
+		}
+		b.AddWeightedDirectedEdge(from, to, w)
+	}
+	bg, err := b.b.Build()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	return FromBin(bg), b.b.Repeated(), nil
+}
+
+// Write writes the edges of g to a file, by path, in any format of
+// util.CreateEdges, with a weight column if g has weights. Edges are
+// written in order of their nodes, so the same graph always gives the same
+// file. Nodes without edges are not written.
+func Write(g Graph, path string) error {
+	writer, err := util.CreateEdges(path)
+	if err != nil {
+		return err
+	}
+	weighted := IsWeighted(g)
+	for _, id := range g.Nodes() {
+		for _, to := range g.Neighbours(id) {
+			if weighted {
+				err = writer.WriteWeighted(id, to, WeightOf(g, id, to), nil)
+			} else {
+				err = writer.Write(id, to, nil)
+			}
+			if err != nil {
+				writer.Close()
+				return err
+			}
+		}
+	}
+	return writer.Close()
+}
@@ -0,0 +1,69 @@
+/*
+Package graph is the graph abstraction shared by the tools: a read-only
+Graph interface over node IDs, a compact CSR implementation built from
+edge streams, and adapters between graphs.
+
+Node IDs are whatever the edge list files use, usually starting from 1.
+*/
+package graph
+
+// Graph is a read-only graph, addressed by node ID.
+type Graph interface {
+	// Nodes returns the IDs of all nodes, in ascending order.
+	// The caller must not modify the slice.
+	Nodes() []int
+	// Neighbours returns the IDs of the nodes id has edges to, in ascending
+	// order. The caller must not modify the slice.
+	Neighbours(id int) []int
+	// Degree returns the number of edges from id.
+	Degree(id int) int
+	// HasEdge returns whether there is an edge from -> to.
+	HasEdge(from, to int) bool
+	// Directed returns whether some edge has no reverse edge.
+	Directed() bool
+}
+
+// Weighted is implemented by graphs that can have edge weights.
+type Weighted interface {
+	// Weight of the edge from -> to, 1 when not set.
+	Weight(from, to int) float64
+	// IsWeighted returns whether any edge has a weight other than 1.
+	IsWeighted() bool
+}
+
+// WeightOf returns the weight of the edge from -> to of g, which is 1 unless
+// g is Weighted.
+func WeightOf(g Graph, from, to int) float64 {
+	if w, ok := g.(Weighted); ok {
+		return w.Weight(from, to)
+	}
+	return 1
+}
+
+// IsWeighted returns whether g is Weighted and has weights other than 1.
+func IsWeighted(g Graph) bool {
+	w, ok := g.(Weighted)
+	return ok && w.IsWeighted()
+}
+
+// IsDirected returns whether some edge of g has no reverse edge, by checking
+// every edge. Graphs can use it to implement Directed.
+func IsDirected(g Graph) bool {
+	for _, from := range g.Nodes() {
+		for _, to := range g.Neighbours(from) {
+			if !g.HasEdge(to, from) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NumEdges returns the number of (directed) edges of g.
+func NumEdges(g Graph) int {
+	total := 0
+	for _, id := range g.Nodes() {
+		total += g.Degree(id)
+	}
+	return total
+}
@@ -0,0 +1,157 @@
+package graph
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder()
+	b.AddDirectedEdge(5, 1)
+	b.AddWeightedDirectedEdge(1, 9, 2)
+	b.AddDirectedEdge(1, 5)
+	b.AddWeightedDirectedEdge(1, 9, 3) // repeated, this weight wins
+	b.AddNode(7)
+	g := b.Build()
+
+	ids := g.Nodes()
+	if len(ids) != 4 || ids[0] != 1 || ids[3] != 9 {
+		t.Errorf("Expected nodes 1, 5, 7, 9, got %v.", ids)
+	}
+	if g.NumEdges() != 3 || NumEdges(g) != 3 {
+		t.Errorf("Expected 3 edges, got %d.", g.NumEdges())
+	}
+	if n := g.Neighbours(1); len(n) != 2 || n[0] != 5 || n[1] != 9 {
+		t.Errorf("Expected neighbours 5, 9 of node 1, got %v.", n)
+	}
+	if g.Degree(7) != 0 || g.Degree(42) != 0 {
+		t.Error("Nodes 7 and 42 should have no edges.")
+	}
+	if !g.HasEdge(5, 1) || g.HasEdge(9, 1) {
+		t.Error("HasEdge is wrong.")
+	}
+	if g.Weight(1, 9) != 3 || g.Weight(1, 5) != 1 || !g.IsWeighted() {
+		t.Errorf("Wrong weights %v and %v.", g.Weight(1, 9), g.Weight(1, 5))
+	}
+	if !g.Directed() {
+		t.Error("1 -> 9 has no reverse, the graph is directed.")
+	}
+}
+
+func TestUndirected(t *testing.T) {
+	b := NewBuilder()
+	b.AddWeightedDirectedEdge(1, 2, 0.5)
+	b.AddEdge(2, 3)
+	u := Undirected(b.Build())
+	if u.Directed() || !u.HasEdge(2, 1) || WeightOf(u, 2, 1) != 0.5 {
+		t.Error("Reverse edge 2 -> 1 missing, or with the wrong weight.")
+	}
+	if NumEdges(u) != 4 {
+		t.Errorf("Expected 4 edges, got %d.", NumEdges(u))
+	}
+	if Undirected(u) != u {
+		t.Error("An undirected graph should be returned as it is.")
+	}
+}
+
+func TestInduced(t *testing.T) {
+	b := NewBuilder()
+	b.AddEdge(1, 2)
+	b.AddEdge(2, 3)
+	b.AddEdge(3, 1)
+	b.AddEdge(3, 4)
+	s := Induced(b.Build(), []int{3, 1, 4, 99})
+
+	if ids := s.Nodes(); len(ids) != 3 || ids[0] != 1 || ids[2] != 4 {
+		t.Errorf("Expected nodes 1, 3, 4, got %v.", ids)
+	}
+	if n := s.Neighbours(3); len(n) != 2 || n[0] != 1 || n[1] != 4 {
+		t.Errorf("Expected neighbours 1, 4 of node 3, got %v.", n)
+	}
+	if s.HasEdge(1, 2) || s.Degree(2) != 0 || s.Directed() {
+		t.Error("Node 2 should not be in the subgraph.")
+	}
+}
+
+func TestRelabel(t *testing.T) {
+	b := NewBuilder()
+	b.AddWeightedEdge(10, 30, 2)
+	b.AddEdge(30, 20)
+	c, ids := Relabel(b.Build())
+
+	if len(ids) != 3 || ids[0] != 10 || ids[1] != 20 || ids[2] != 30 {
+		t.Errorf("Expected original IDs 10, 20, 30, got %v.", ids)
+	}
+	if n := c.Nodes(); len(n) != 3 || n[0] != 1 || n[2] != 3 {
+		t.Errorf("Expected nodes 1, 2, 3, got %v.", n)
+	}
+	if !c.HasEdge(1, 3) || !c.HasEdge(2, 3) || c.HasEdge(1, 2) || c.Weight(3, 1) != 2 {
+		t.Error("Edges or weights not relabelled.")
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graph")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "g.txt")
+	if err := ioutil.WriteFile(path, []byte("# comment\n1 2 0.5\n2 1 0.5\n2 3 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Nodes()) != 3 || g.NumEdges() != 3 || g.Weight(2, 1) != 0.5 || !g.Directed() {
+		t.Errorf("Unexpected graph: %d nodes, %d edges.", len(g.Nodes()), g.NumEdges())
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graph")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := NewBuilder()
+	b.AddWeightedDirectedEdge(2, 1, 0.5)
+	b.AddDirectedEdge(1, 3)
+	b.AddNode(4)
+	path := filepath.Join(dir, "g.csv")
+	if err := Write(b.Build(), path); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != "1,3,1\n2,1,0.5\n" {
+		t.Errorf("Unexpected file %q.", raw)
+	}
+}
+
+func TestReadDuplicates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graph")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in.csv")
+	if err := ioutil.WriteFile(in, []byte("1,2\n2,1\n1,2\n1,2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	g, n, err := ReadDuplicates(in)
+	if err != nil || n != 2 {
+		t.Errorf("Expected 2 duplicates, got %d (%v).", n, err)
+	}
+	if g != nil && g.NumEdges() != 2 {
+		t.Errorf("Expected 2 edges, got %d.", g.NumEdges())
+	}
+}
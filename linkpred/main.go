@@ -0,0 +1,332 @@
+/*
+Link prediction evaluation of the similarity algorithm.
+
+Takes the output of `conncomp -action remove`: a training graph and the
+list of edges removed from it. Trains sim on the training graph, then
+checks how well the removed edges score against sampled non-edges.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/vladvelici/graph-dataset-tools/sim"
+	"github.com/vladvelici/graph-dataset-tools/util"
+)
+
+var (
+	flagTrain     = flag.String("train", "", "Training graph (edge list, node IDs from 1). Must be CSV for the matlab and octave backends.")
+	flagTest      = flag.String("test", "", "Held-out edges, e.g. the removed_ file of conncomp.")
+	flagBackend   = flag.String("backend", sim.BackendNative, "Eigen backend: native, matlab or octave.")
+	flagScripts   = flag.String("scripts", sim.DefaultScriptDir, "Directory of the Matlab/Octave scripts.")
+	flagBinary    = flag.String("binary", "", "Matlab or Octave executable. Empty uses the default.")
+	flagMu        = flag.Float64("mu", 0.5, "Penalising factor.")
+	flagK         = flag.Int("k", 20, "Number of eigenvalues to use.")
+	flagNonEdges  = flag.String("nonedges", "", "Non-edges to use, e.g. the nonedges_ file of conncomp remove -neg N. Sampled if empty.")
+	flagNegatives = flag.Float64("negatives", 1, "Number of non-edges to sample per held-out edge, without -nonedges.")
+	flagAt        = flag.Int("at", 100, "k for precision@k.")
+	flagSeed      = flag.Int64("seed", 1, "Seed for sampling non-edges.")
+	flagWorkers   = flag.Int("workers", 0, "Goroutines used to rank nodes. 0 uses GOMAXPROCS.")
+	flagHelp      = flag.Bool("help", false, "Show this help message")
+	flagH         = flag.Bool("h", false, "Show this help message")
+)
+
+var helpMessage = `linkpred evaluates sim as a link predictor.
+
+Usage:
+
+linkpred -train <graph> -test <held-out edges> [flags]
+
+Split a graph with 'conncomp -action remove' first: its output graph is the
+training graph, and the removed_ file has the held-out edges. If it was
+given -neg N, pass its nonedges_ file with -nonedges; otherwise non-edges
+are sampled uniformly.
+
+Reported metrics:
+
+  AUC           Probability that a held-out edge scores higher than a
+                sampled non-edge.
+  precision@k   Fraction of held-out edges among the k best scored pairs of
+                held-out edges and sampled non-edges.
+  MAP, MRR      Mean average precision and mean reciprocal rank of held-out
+                edges, ranking all non-neighbours of each of their nodes.
+
+Full list of flags:
+
+`
+
+func help() {
+	fmt.Println(helpMessage)
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = help
+	flag.Parse()
+
+	if *flagHelp || *flagH {
+		help()
+		return
+	}
+
+	if *flagTrain == "" || *flagTest == "" {
+		fmt.Println("Need both -train and -test. See -help.")
+		return
+	}
+
+	err := evaluate()
+	if err != nil {
+		fmt.Println(err)
+	}
+}
+
+// Undirected edge, always with a < b.
+type pair struct {
+	a, b int
+}
+
+func newPair(a, b int) pair {
+	if a > b {
+		a, b = b, a
+	}
+	return pair{a, b}
+}
+
+// Edge set with adjacency lists, treating edges as undirected.
+type edgeSet struct {
+	pairs map[pair]bool
+	adj   map[int][]int
+}
+
+func newEdgeSet() *edgeSet {
+	return &edgeSet{make(map[pair]bool), make(map[int][]int)}
+}
+
+func (s *edgeSet) add(a, b int) {
+	p := newPair(a, b)
+	if a == b || s.pairs[p] {
+		return
+	}
+	s.pairs[p] = true
+	s.adj[a] = append(s.adj[a], b)
+	s.adj[b] = append(s.adj[b], a)
+}
+
+func (s *edgeSet) has(a, b int) bool {
+	return s.pairs[newPair(a, b)]
+}
+
+func readEdges(path string) (*edgeSet, error) {
+	reader, err := util.OpenEdges(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	set := newEdgeSet()
+	for {
+		a, b, _, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err.Error())
+		}
+		set.add(a, b)
+	}
+	return set, nil
+}
+
+func evaluate() error {
+	train, err := readEdges(*flagTrain)
+	if err != nil {
+		return err
+	}
+	test, err := readEdges(*flagTest)
+	if err != nil {
+		return err
+	}
+
+	backend, err := sim.NewBackend(sim.Config{
+		Backend:   *flagBackend,
+		ScriptDir: *flagScripts,
+		Binary:    *flagBinary,
+	})
+	if err != nil {
+		return err
+	}
+	q, z, err := backend.Compute(*flagTrain, *flagMu, *flagK)
+	if err != nil {
+		return err
+	}
+	result := sim.FromQZ(q, z)
+	n := result.Len()
+
+	// higher is better, nodes from 1
+	score := func(a, b int) float64 {
+		return -result.DistanceSim(a-1, b-1)
+	}
+
+	var pos []float64
+	for p := range test.pairs {
+		if p.a < 1 || p.b > n {
+			fmt.Printf("Held-out edge (%d, %d) has a node not in the training graph. Skipping.\n", p.a, p.b)
+			continue
+		}
+		pos = append(pos, score(p.a, p.b))
+	}
+	if len(pos) == 0 {
+		return fmt.Errorf("No held-out edges to evaluate.")
+	}
+
+	var negatives []pair
+	if *flagNonEdges != "" {
+		set, err := readEdges(*flagNonEdges)
+		if err != nil {
+			return err
+		}
+		for p := range set.pairs {
+			if p.a >= 1 && p.b <= n && !train.has(p.a, p.b) && !test.has(p.a, p.b) {
+				negatives = append(negatives, p)
+			}
+		}
+	} else {
+		rnd := rand.New(rand.NewSource(*flagSeed))
+		negatives = sampleNonEdges(rnd, n, int(*flagNegatives*float64(len(pos))), train, test)
+	}
+	neg := make([]float64, len(negatives))
+	for i, p := range negatives {
+		neg[i] = score(p.a, p.b)
+	}
+
+	mapScore, mrr := rankingMetrics(result, train, test)
+
+	fmt.Printf("held-out edges\t%d\n", len(pos))
+	fmt.Printf("non-edges\t%d\n", len(neg))
+	fmt.Printf("AUC\t\t%.4f\n", auc(pos, neg))
+	fmt.Printf("precision@%d\t%.4f\n", *flagAt, precisionAt(pos, neg, *flagAt))
+	fmt.Printf("MAP\t\t%.4f\n", mapScore)
+	fmt.Printf("MRR\t\t%.4f\n", mrr)
+	return nil
+}
+
+// Sample count distinct node pairs (nodes 1..n) that are edges of neither
+// graph. Gives up after too many misses, on graphs that are nearly complete.
+func sampleNonEdges(rnd *rand.Rand, n, count int, graphs ...*edgeSet) []pair {
+	res := make([]pair, 0, count)
+	seen := make(map[pair]bool)
+	for misses := 0; len(res) < count && misses < 100*count+100; {
+		a, b := rnd.Intn(n)+1, rnd.Intn(n)+1
+		p := newPair(a, b)
+		ok := a != b && !seen[p]
+		for _, g := range graphs {
+			ok = ok && !g.has(a, b)
+		}
+		if !ok {
+			misses++
+			continue
+		}
+		seen[p] = true
+		res = append(res, p)
+	}
+	return res
+}
+
+// For every node with held-out edges, rank all other nodes that are not its
+// neighbours in train, and average the AP and RR of its held-out neighbours.
+func rankingMetrics(result *sim.Result, train, test *edgeSet) (float64, float64) {
+	n := result.Len()
+	var queries []int
+	for node := range test.adj {
+		if node >= 1 && node <= n {
+			queries = append(queries, node)
+		}
+	}
+	if len(queries) == 0 {
+		return 0, 0
+	}
+
+	workers := *flagWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	type metrics struct {
+		ap, rr float64
+	}
+	jobs := make(chan int)
+	results := make(chan metrics)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for node := range jobs {
+				ranks := heldOutRanks(result, node, train, test)
+				results <- metrics{averagePrecision(ranks, len(test.adj[node])), reciprocalRank(ranks)}
+			}
+		}()
+	}
+	go func() {
+		for _, node := range queries {
+			jobs <- node
+		}
+		close(jobs)
+		wg.Wait()
+		close(results)
+	}()
+
+	var sumAP, sumRR float64
+	for m := range results {
+		sumAP += m.ap
+		sumRR += m.rr
+	}
+	return sumAP / float64(len(queries)), sumRR / float64(len(queries))
+}
+
+// Ranks, from 1 and in increasing order, of the held-out neighbours of node
+// among all other nodes that are not its neighbours in train. Nodes are
+// ranked most similar first, ties by node ID, like sim.Result.Nearest.
+// Only the held-out neighbours are kept in memory: the other nodes are just
+// counted between them.
+func heldOutRanks(result *sim.Result, node int, train, test *edgeSet) []int {
+	n := result.Len()
+	var relevant []sim.Neighbour
+	for _, other := range test.adj[node] {
+		if other >= 1 && other <= n && !train.has(node, other) {
+			relevant = append(relevant, sim.Neighbour{Node: other - 1, Score: result.DistanceSim(node-1, other-1)})
+		}
+	}
+	sort.Slice(relevant, func(i, j int) bool { return closer(relevant[i], relevant[j]) })
+
+	// before[i] is the number of other nodes ranked just before relevant[i]
+	before := make([]int, len(relevant)+1)
+	for other := 1; other <= n; other++ {
+		if other == node || train.has(node, other) || test.has(node, other) {
+			continue
+		}
+		nb := sim.Neighbour{Node: other - 1, Score: result.DistanceSim(node-1, other-1)}
+		before[sort.Search(len(relevant), func(i int) bool { return closer(nb, relevant[i]) })]++
+	}
+
+	ranks := make([]int, len(relevant))
+	others := 0
+	for i := range relevant {
+		others += before[i]
+		ranks[i] = others + i + 1
+	}
+	return ranks
+}
+
+// Whether a ranks before b: smaller distance first, ties by node ID.
+func closer(a, b sim.Neighbour) bool {
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	return a.Node < b.Node
+}
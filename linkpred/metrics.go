@@ -0,0 +1,80 @@
+package main
+
+import "sort"
+
+// auc is the area under the ROC curve: the probability that a random
+// positive scores higher than a random negative, counting ties as half.
+func auc(pos, neg []float64) float64 {
+	if len(pos) == 0 || len(neg) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(neg))
+	copy(sorted, neg)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, p := range pos {
+		below := sort.SearchFloat64s(sorted, p)
+		ties := sort.Search(len(sorted), func(i int) bool { return sorted[i] > p }) - below
+		sum += float64(below) + float64(ties)/2
+	}
+	return sum / float64(len(pos)) / float64(len(neg))
+}
+
+// precisionAt is the fraction of positives among the k highest scores of
+// pos and neg together. Ties are broken in favour of negatives.
+func precisionAt(pos, neg []float64, k int) float64 {
+	type item struct {
+		score    float64
+		positive bool
+	}
+	all := make([]item, 0, len(pos)+len(neg))
+	for _, s := range pos {
+		all = append(all, item{s, true})
+	}
+	for _, s := range neg {
+		all = append(all, item{s, false})
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].score != all[j].score {
+			return all[i].score > all[j].score
+		}
+		return !all[i].positive && all[j].positive
+	})
+
+	if k > len(all) {
+		k = len(all)
+	}
+	if k <= 0 {
+		return 0
+	}
+	hits := 0
+	for _, it := range all[:k] {
+		if it.positive {
+			hits++
+		}
+	}
+	return float64(hits) / float64(k)
+}
+
+// averagePrecision of a ranking, given the ranks (from 1, increasing) of
+// the relevant items that were ranked. total is the number of relevant
+// items, some of which may not be ranked.
+func averagePrecision(ranks []int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	var sum float64
+	for i, rank := range ranks {
+		sum += float64(i+1) / float64(rank)
+	}
+	return sum / float64(total)
+}
+
+// reciprocalRank is 1 / the rank of the first relevant item, or 0 if none.
+func reciprocalRank(ranks []int) float64 {
+	if len(ranks) == 0 {
+		return 0
+	}
+	return 1 / float64(ranks[0])
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+	"github.com/vladvelici/graph-dataset-tools/sim"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestAuc(t *testing.T) {
+	if a := auc([]float64{3, 4}, []float64{1, 2}); !near(a, 1) {
+		t.Errorf("Perfect separation should have AUC 1, got %f.", a)
+	}
+	if a := auc([]float64{1, 2}, []float64{3, 4}); !near(a, 0) {
+		t.Errorf("Inverted separation should have AUC 0, got %f.", a)
+	}
+	if a := auc([]float64{1}, []float64{1}); !near(a, 0.5) {
+		t.Errorf("A tie should count as half, got %f.", a)
+	}
+	// 3 > 2, 3 > 1, 1 = 1, 1 < 2: (1 + 1 + 0.5 + 0) / 4
+	if a := auc([]float64{3, 1}, []float64{2, 1}); !near(a, 0.625) {
+		t.Errorf("Expected 0.625, got %f.", a)
+	}
+}
+
+func TestPrecisionAt(t *testing.T) {
+	pos := []float64{5, 3, 1}
+	neg := []float64{4, 2, 0}
+	cases := map[int]float64{1: 1, 2: 0.5, 3: 2.0 / 3, 6: 0.5, 10: 0.5}
+	for k, expected := range cases {
+		if p := precisionAt(pos, neg, k); !near(p, expected) {
+			t.Errorf("precision@%d: expected %f, got %f.", k, expected, p)
+		}
+	}
+}
+
+func TestAveragePrecision(t *testing.T) {
+	// relevant at ranks 1 and 3: (1/1 + 2/3) / 2
+	if ap := averagePrecision([]int{1, 3}, 2); !near(ap, (1+2.0/3)/2) {
+		t.Errorf("Unexpected AP %f.", ap)
+	}
+	// one relevant item was never ranked
+	if ap := averagePrecision([]int{1}, 2); !near(ap, 0.5) {
+		t.Errorf("Unexpected AP %f.", ap)
+	}
+}
+
+func TestReciprocalRank(t *testing.T) {
+	if rr := reciprocalRank([]int{3, 4}); !near(rr, 1.0/3) {
+		t.Errorf("Expected 1/3, got %f.", rr)
+	}
+	if rr := reciprocalRank(nil); rr != 0 {
+		t.Errorf("Expected 0, got %f.", rr)
+	}
+}
+
+// heldOutRanks should agree with ranking every node with Nearest.
+func TestHeldOutRanks(t *testing.T) {
+	q := mat64.NewDense(1, 1, []float64{1})
+	z := mat64.NewDense(6, 1, []float64{0, 1, 2, 2, 5, -3})
+	result := sim.FromQZ(q, z)
+
+	train, test := newEdgeSet(), newEdgeSet()
+	train.add(1, 2)
+	test.add(1, 4)
+	test.add(1, 6)
+	test.add(1, 2) // also in train, so never ranked
+
+	var expected []int
+	rank := 0
+	for _, nb := range result.Nearest(0, result.Len()) {
+		if train.has(1, nb.Node+1) {
+			continue
+		}
+		rank++
+		if test.has(1, nb.Node+1) {
+			expected = append(expected, rank)
+		}
+	}
+
+	ranks := heldOutRanks(result, 1, train, test)
+	if len(ranks) != len(expected) {
+		t.Fatalf("Expected ranks %v, got %v.", expected, ranks)
+	}
+	for i := range ranks {
+		if ranks[i] != expected[i] {
+			t.Errorf("Expected ranks %v, got %v.", expected, ranks)
+			break
+		}
+	}
+}
@@ -1,25 +1,36 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 
 	"github.com/vladvelici/graph-dataset-tools/sim"
 )
 
+var (
+	flagInput   = flag.String("input", "/Users/vlad/Projects/uni/project/datasets/fb/egonets/test/a_g_fb.csv", "Input graph (CSV edge list).")
+	flagBackend = flag.String("backend", sim.BackendNative, "Eigen backend: native, matlab or octave.")
+	flagScripts = flag.String("scripts", "./sim/"+sim.DefaultScriptDir, "Directory of the Matlab/Octave scripts.")
+	flagBinary  = flag.String("binary", "", "Matlab or Octave executable. Empty uses the default.")
+	flagMu      = flag.Float64("mu", 0.5, "Penalising factor.")
+	flagK       = flag.Int("k", 20, "Number of eigenvalues to use.")
+)
+
 func main() {
-	outputPath := "../../output.txt"
-	inputPath := "/Users/vlad/Projects/uni/project/datasets/fb/egonets/test/a_g_fb.csv"
-	mu := 0.5
-	k := 20
+	flag.Parse()
 
-	sim.Path = "./sim/" + sim.Path
-	err := sim.EigenRaw(inputPath, outputPath, mu, k)
+	backend, err := sim.NewBackend(sim.Config{
+		Backend:   *flagBackend,
+		ScriptDir: *flagScripts,
+		Binary:    *flagBinary,
+	})
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	q, z, err := sim.ParseEigenOutput("output.txt")
+
+	q, z, err := backend.Compute(*flagInput, *flagMu, *flagK)
 	if err != nil {
 		fmt.Println(err)
 		return
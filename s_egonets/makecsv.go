@@ -7,6 +7,8 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/vladvelici/graph-dataset-tools/util"
 )
 
 var mapping = make(map[int]int)
@@ -25,12 +27,11 @@ type pair struct {
 	a, b int
 }
 
-func (p *pair) write(output io.Writer) error {
+func (p *pair) write(output util.EdgeWriter) error {
 	if p == nil {
 		return fmt.Errorf("empty pair?? What the hack??")
 	}
-	_, err := fmt.Fprintf(output, "%d, %d\n", mapping[p.a], mapping[p.b])
-	return err
+	return output.Write(mapping[p.a], mapping[p.b], nil)
 }
 
 func addPair(a, b string) error {
@@ -91,12 +92,12 @@ func main() {
 		return
 	}
 
-	outputFile, err := os.Create(os.Args[len(os.Args)-1])
+	// any edge list format of util.CreateEdges, from the extension
+	output, err := util.CreateEdges(os.Args[len(os.Args)-1])
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	output := bufio.NewWriter(outputFile)
 
 	for _, name := range os.Args[1 : len(os.Args)-1] {
 		err = readFile(name)
@@ -111,8 +112,9 @@ func main() {
 		}
 	}
 
-	output.Flush()
-	outputFile.Close()
+	if err := output.Close(); err != nil {
+		fmt.Println(err)
+	}
 
 	fmt.Printf("Wrote %d pairs. Last node ID allocated: %d (starting at 1).\n", len(pairs), id-1)
 }
@@ -0,0 +1,199 @@
+package sim
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/vladvelici/graph-dataset-tools/util"
+)
+
+// AllPairsOptions controls which pairs AllPairs, WriteCSV and WriteDense
+// produce, and how much memory they use.
+type AllPairsOptions struct {
+	// BlockSize is the number of rows computed at a time. Memory use is
+	// about BlockSize * Len() floats. Zero picks a block of ~64MB.
+	BlockSize int
+	// Threshold drops pairs with a larger DistanceSim, when ThresholdSet is
+	// true. Zero is a valid threshold.
+	Threshold    float64
+	ThresholdSet bool
+	// TopK, when positive, keeps only the TopK most similar nodes per row.
+	TopK int
+	// Upper keeps only pairs (a, b) with a < b. Ignored when TopK is set,
+	// since the top K of a row is not symmetric.
+	Upper bool
+}
+
+func (o AllPairsOptions) blockSize(n int) int {
+	b := o.BlockSize
+	if b <= 0 {
+		b = (1 << 23) / n
+	}
+	if b < 1 {
+		b = 1
+	}
+	if b > n {
+		b = n
+	}
+	return b
+}
+
+// Whether the pair (a, b) with the given score passes the filters that do
+// not depend on the rest of the row.
+func (o AllPairsOptions) keep(a, b int, score float64) bool {
+	if o.ThresholdSet && score > o.Threshold {
+		return false
+	}
+	if o.Upper && o.TopK <= 0 && a >= b {
+		return false
+	}
+	return true
+}
+
+// Width of the column tiles, so a tile of Z rows stays in cache.
+const tileCols = 256
+
+// Compute DistanceSim for every node against the rows [start, start+len(block)/n),
+// tile by tile. block is filled row-major.
+func (r *Result) rowBlock(start int, block []float64) {
+	n, k := len(r.norms), r.k
+	rows := len(block) / n
+	for c0 := 0; c0 < n; c0 += tileCols {
+		c1 := c0 + tileCols
+		if c1 > n {
+			c1 = n
+		}
+		for i := 0; i < rows; i++ {
+			a := start + i
+			zqa := r.zq[a*k : (a+1)*k]
+			out := block[i*n : (i+1)*n]
+			for b := c0; b < c1; b++ {
+				out[b] = r.norms[a] + r.norms[b] - 2*dot(zqa, r.zraw[b*k:(b+1)*k])
+			}
+		}
+	}
+}
+
+// Call f for each block of full rows of the similarity matrix, in order.
+func (r *Result) eachRowBlock(opts AllPairsOptions, f func(start int, block []float64) error) error {
+	r.prepare()
+	n := len(r.norms)
+	if n == 0 {
+		return nil
+	}
+	size := opts.blockSize(n)
+	buf := make([]float64, size*n)
+	for start := 0; start < n; start += size {
+		rows := size
+		if start+rows > n {
+			rows = n - start
+		}
+		block := buf[:rows*n]
+		r.rowBlock(start, block)
+		if err := f(start, block); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AllPairs calls f with the DistanceSim of every pair (a, b), a != b, that
+// passes the filters in opts, row by row. Node IDs start from 0.
+// Stops and returns the first error returned by f.
+func (r *Result) AllPairs(opts AllPairsOptions, f func(a, b int, score float64) error) error {
+	return r.eachRowBlock(opts, func(start int, block []float64) error {
+		n := len(r.norms)
+		for i := 0; i < len(block)/n; i++ {
+			a := start + i
+			row := block[i*n : (i+1)*n]
+
+			if opts.TopK > 0 {
+				top := newTopN(opts.TopK)
+				for b, score := range row {
+					if b != a && opts.keep(a, b, score) {
+						top.offer(Neighbour{b, score})
+					}
+				}
+				for _, nb := range top.sorted() {
+					if err := f(a, nb.Node, nb.Score); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			for b, score := range row {
+				if b == a || !opts.keep(a, b, score) {
+					continue
+				}
+				if err := f(a, b, score); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// WriteCSV writes the pairs from AllPairs as CSV triples (a, b, score).
+// Like the input edge lists, node IDs in the file start from 1.
+func (r *Result) WriteCSV(w io.Writer, opts AllPairsOptions) error {
+	writer := util.NewWriter(w)
+	extra := make([]string, 1)
+	err := r.AllPairs(opts, func(a, b int, score float64) error {
+		extra[0] = strconv.FormatFloat(score, 'g', -1, 64)
+		return writer.Write(a+1, b+1, extra)
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// WriteDense writes the whole Len() x Len() similarity matrix in binary:
+// the number of rows and columns as little endian int64, then the scores as
+// little endian float64, row by row. Pairs dropped by opts are written as NaN.
+// The diagonal, which AllPairs never emits, is always written, whatever opts.
+func (r *Result) WriteDense(w io.Writer, opts AllPairsOptions) error {
+	r.prepare()
+	n := len(r.norms)
+	if err := binary.Write(w, binary.LittleEndian, [2]int64{int64(n), int64(n)}); err != nil {
+		return err
+	}
+
+	nan := math.NaN()
+	out := make([]byte, 8*n)
+	return r.eachRowBlock(opts, func(start int, block []float64) error {
+		for i := 0; i < len(block)/n; i++ {
+			a := start + i
+			row := block[i*n : (i+1)*n]
+
+			var top map[int]bool
+			if opts.TopK > 0 {
+				t := newTopN(opts.TopK)
+				for b, score := range row {
+					if b != a && opts.keep(a, b, score) {
+						t.offer(Neighbour{b, score})
+					}
+				}
+				top = make(map[int]bool, opts.TopK)
+				for _, nb := range t.h {
+					top[nb.Node] = true
+				}
+			}
+
+			for b, score := range row {
+				if b != a && ((top != nil && !top[b]) || !opts.keep(a, b, score)) {
+					score = nan
+				}
+				binary.LittleEndian.PutUint64(out[8*b:], math.Float64bits(score))
+			}
+			if _, err := w.Write(out); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,156 @@
+package sim
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestAllPairs(t *testing.T) {
+	r := pointsResult()
+	n := r.Len()
+
+	seen := 0
+	err := r.AllPairs(AllPairsOptions{BlockSize: 2}, func(a, b int, score float64) error {
+		seen++
+		if a == b {
+			t.Errorf("Self pair (%d, %d) emitted.", a, b)
+		}
+		if expected := r.DistanceSim(a, b); math.Abs(score-expected) > epsilon {
+			t.Errorf("(%d, %d): %f, DistanceSim says %f.", a, b, score, expected)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen != n*(n-1) {
+		t.Errorf("Expected %d pairs, got %d.", n*(n-1), seen)
+	}
+}
+
+func TestAllPairsFilters(t *testing.T) {
+	r := pointsResult()
+
+	count := func(opts AllPairsOptions) int {
+		c := 0
+		r.AllPairs(opts, func(a, b int, score float64) error {
+			if opts.ThresholdSet && score > opts.Threshold {
+				t.Errorf("(%d, %d): %f is above the threshold.", a, b, score)
+			}
+			if opts.Upper && a >= b {
+				t.Errorf("(%d, %d) is not in the upper triangle.", a, b)
+			}
+			c++
+			return nil
+		})
+		return c
+	}
+
+	if c := count(AllPairsOptions{Upper: true}); c != 10 {
+		t.Errorf("Upper triangle should have 10 pairs, got %d.", c)
+	}
+	// pairs within distance 4: 0-1, 0-4, 0-2, 1-4 each way
+	if c := count(AllPairsOptions{Threshold: 4, ThresholdSet: true}); c != 8 {
+		t.Errorf("Expected 8 pairs within distance 4, got %d.", c)
+	}
+	// all points are distinct, so a threshold of 0 drops every pair
+	if c := count(AllPairsOptions{ThresholdSet: true}); c != 0 {
+		t.Errorf("Expected no pairs within distance 0, got %d.", c)
+	}
+}
+
+func TestAllPairsTopK(t *testing.T) {
+	r := pointsResult()
+	rows := make(map[int][]Neighbour)
+	r.AllPairs(AllPairsOptions{TopK: 2, BlockSize: 3}, func(a, b int, score float64) error {
+		rows[a] = append(rows[a], Neighbour{b, score})
+		return nil
+	})
+
+	for a := 0; a < r.Len(); a++ {
+		expected := r.Nearest(a, 2)
+		if len(rows[a]) != len(expected) {
+			t.Fatalf("Row %d: %v, Nearest says %v.", a, rows[a], expected)
+		}
+		for i := range expected {
+			if rows[a][i] != expected[i] {
+				t.Errorf("Row %d: %v, Nearest says %v.", a, rows[a], expected)
+				break
+			}
+		}
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	r := pointsResult()
+	var buf bytes.Buffer
+	if err := r.WriteCSV(&buf, AllPairsOptions{TopK: 1}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != r.Len() {
+		t.Fatalf("Expected %d lines, got %d:\n%s", r.Len(), len(lines), buf.String())
+	}
+	if lines[0] != "1,2,1" {
+		t.Errorf("First line should be 1,2,1 (IDs from 1), got %s.", lines[0])
+	}
+}
+
+func TestWriteDense(t *testing.T) {
+	r := pointsResult()
+	var buf bytes.Buffer
+	if err := r.WriteDense(&buf, AllPairsOptions{BlockSize: 2, Threshold: 4, ThresholdSet: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	var dims [2]int64
+	if err := binary.Read(&buf, binary.LittleEndian, &dims); err != nil {
+		t.Fatal(err)
+	}
+	n := r.Len()
+	if dims[0] != int64(n) || dims[1] != int64(n) {
+		t.Fatalf("Wrong dimensions %v.", dims)
+	}
+
+	data := make([]float64, n*n)
+	if err := binary.Read(&buf, binary.LittleEndian, data); err != nil {
+		t.Fatal(err)
+	}
+	for a := 0; a < n; a++ {
+		for b := 0; b < n; b++ {
+			expected := r.DistanceSim(a, b)
+			got := data[a*n+b]
+			if expected > 4 {
+				if !math.IsNaN(got) {
+					t.Errorf("(%d, %d) should be dropped, got %f.", a, b, got)
+				}
+			} else if math.Abs(got-expected) > epsilon {
+				t.Errorf("(%d, %d): %f, expected %f.", a, b, got, expected)
+			}
+		}
+	}
+}
+
+// The diagonal is written the same way with or without TopK.
+func TestWriteDenseDiagonal(t *testing.T) {
+	r := pointsResult()
+	n := r.Len()
+	for _, opts := range []AllPairsOptions{{}, {TopK: 1}, {Upper: true}, {ThresholdSet: true}} {
+		var buf bytes.Buffer
+		if err := r.WriteDense(&buf, opts); err != nil {
+			t.Fatal(err)
+		}
+		data := make([]float64, 2+n*n)
+		if err := binary.Read(&buf, binary.LittleEndian, data); err != nil {
+			t.Fatal(err)
+		}
+		for a := 0; a < n; a++ {
+			if d := data[2+a*n+a]; math.IsNaN(d) || math.Abs(d) > epsilon {
+				t.Errorf("%+v: diagonal (%d, %d) is %f.", opts, a, a, d)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package sim
+
+import (
+	"fmt"
+
+	"github.com/gonum/matrix/mat64"
+)
+
+// EigenBackend computes the Q and Z matrices used by Result.
+type EigenBackend interface {
+	// Compute reads the edge list at path graph (node IDs starting from 1)
+	// and returns Q and Z for penalising factor mu, using k eigenvalues.
+	Compute(graph string, mu float64, k int) (*mat64.Dense, *mat64.Dense, error)
+}
+
+// Native computes Q and Z in Go, without any external program.
+type Native struct {
+	// Options for the Lanczos eigensolver.
+	Options LanczosOptions
+}
+
+// Compute Q and Z with the Go port of similarity.m.
+func (n Native) Compute(graph string, mu float64, k int) (*mat64.Dense, *mat64.Dense, error) {
+	return EigenNativeOptions(graph, mu, k, n.Options)
+}
+
+// Eigen computes Q and Z for the graph at inputPath with the Native backend.
+func Eigen(inputPath string, mu float64, k int) (*mat64.Dense, *mat64.Dense, error) {
+	return Native{}.Compute(inputPath, mu, k)
+}
+
+// Names of the backends understood by NewBackend.
+const (
+	BackendNative = "native"
+	BackendMatlab = "matlab"
+	BackendOctave = "octave"
+)
+
+// Config describes which EigenBackend to use, for example from command line flags.
+type Config struct {
+	// Backend is one of BackendNative (the default), BackendMatlab or BackendOctave.
+	Backend string
+	// ScriptDir is the mlscript directory, for Matlab and Octave.
+	ScriptDir string
+	// Binary is the Matlab or Octave executable. Empty uses the default.
+	Binary string
+	// Lanczos options, for the native backend.
+	Lanczos LanczosOptions
+}
+
+// NewBackend creates the backend described by c.
+func NewBackend(c Config) (EigenBackend, error) {
+	switch c.Backend {
+	case "", BackendNative:
+		return Native{Options: c.Lanczos}, nil
+	case BackendMatlab:
+		return Matlab{Dir: c.ScriptDir, Binary: c.Binary}, nil
+	case BackendOctave:
+		return Octave{Dir: c.ScriptDir, Binary: c.Binary}, nil
+	}
+	return nil, fmt.Errorf("Unknown eigen backend %q. Use %s, %s or %s.", c.Backend, BackendNative, BackendMatlab, BackendOctave)
+}
@@ -1,42 +1,116 @@
 package sim
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/gonum/matrix/mat64"
 )
 
-var Path = "mlscript/"
+// Default directory of the Matlab/Octave scripts, relative to the sim package.
+const DefaultScriptDir = "mlscript/"
+
+// Script directory and name used by EigenRaw.
+var Path = DefaultScriptDir
 var ScriptName = "train.sh"
 
 // Run the script at Path with arguments: inputPath, outputPath, mu, k.
+// Same as Matlab{Dir: Path, Script: ScriptName}.Run.
 func EigenRaw(inputPath, outputPath string, mu float64, k int) error {
-	attr := &os.ProcAttr{
-		Dir:   Path,
-		Files: []*os.File{os.Stdin, os.Stdout, os.Stderr},
+	return Matlab{Dir: Path, Script: ScriptName}.Run(inputPath, outputPath, mu, k)
+}
+
+// Matlab runs the Matlab implementation (mlscript/train.m) through train.sh.
+type Matlab struct {
+	// Dir is the directory holding the scripts. Defaults to DefaultScriptDir.
+	Dir string
+	// Script is the shell script that starts Matlab. Defaults to "train.sh".
+	Script string
+	// Binary is the Matlab executable, passed to the script as MATLAB_PATH.
+	// Empty keeps the path hardcoded in the script.
+	Binary string
+}
+
+// Compute runs Matlab on the graph and parses its output.
+func (m Matlab) Compute(graph string, mu float64, k int) (*mat64.Dense, *mat64.Dense, error) {
+	return runAndParse(graph, mu, k, m.Run)
+}
+
+// Run the script with arguments: inputPath, mu, k, outputPath.
+func (m Matlab) Run(inputPath, outputPath string, mu float64, k int) error {
+	script := m.Script
+	if script == "" {
+		script = "train.sh"
 	}
-	proc, err := os.StartProcess(ScriptName, []string{ScriptName, inputPath, fmt.Sprint(mu), strconv.Itoa(k), outputPath}, attr)
-	if err != nil {
-		return err
+	cmd := exec.Command("./"+script, inputPath, fmt.Sprint(mu), strconv.Itoa(k), outputPath)
+	if m.Binary != "" {
+		cmd.Env = append(os.Environ(), "MATLAB_PATH="+m.Binary)
 	}
+	return runScript(cmd, m.Dir, "Matlab")
+}
 
-	state, err := proc.Wait()
-	if err != nil {
-		return err
-	}
+// Octave runs the Matlab implementation (mlscript/train.m) with GNU Octave.
+type Octave struct {
+	// Dir is the directory holding the scripts. Defaults to DefaultScriptDir.
+	Dir string
+	// Binary is the Octave executable. Defaults to "octave", from $PATH.
+	Binary string
+}
+
+// Compute runs Octave on the graph and parses its output.
+func (o Octave) Compute(graph string, mu float64, k int) (*mat64.Dense, *mat64.Dense, error) {
+	return runAndParse(graph, mu, k, o.Run)
+}
 
-	if !state.Success() {
-		return fmt.Errorf("Matlab (eigen) process finished with errors (non-zero code).")
+// Run train.m with arguments: inputPath, mu, k, outputPath.
+func (o Octave) Run(inputPath, outputPath string, mu float64, k int) error {
+	binary := o.Binary
+	if binary == "" {
+		binary = "octave"
 	}
+	call := fmt.Sprintf("train(%s, %s, %s, %s);", octaveString(inputPath), octaveString(fmt.Sprint(mu)),
+		octaveString(strconv.Itoa(k)), octaveString(outputPath))
+	cmd := exec.Command(binary, "--no-gui", "--no-window-system", "--quiet", "--eval", call)
+	return runScript(cmd, o.Dir, "Octave")
+}
 
-	return nil
+// Quote a string for Matlab/Octave code.
+func octaveString(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
 }
 
-// Run the script, generate a temporary file for output, parse it, delete it.
-func Eigen(inputPath string, mu float64, k int) (*mat64.Dense, *mat64.Dense, error) {
+// Run cmd in the script directory dir, forwarding its output.
+func runScript(cmd *exec.Cmd, dir, name string) error {
+	if dir == "" {
+		dir = DefaultScriptDir
+	}
+	cmd.Dir = dir
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if _, ok := err.(*exec.ExitError); ok {
+		return fmt.Errorf("%s (eigen) process finished with errors (non-zero code).", name)
+	}
+	return err
+}
+
+// Run a script, generate a temporary file for output, parse it, delete it.
+func runAndParse(inputPath string, mu float64, k int, run func(inputPath, outputPath string, mu float64, k int) error) (*mat64.Dense, *mat64.Dense, error) {
+	// the scripts run in their own directory
+	inputPath, err := filepath.Abs(inputPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	file, err := ioutil.TempFile("", "eigen_output")
 	if err != nil {
 		return nil, nil, err
@@ -50,7 +124,7 @@ func Eigen(inputPath string, mu float64, k int) (*mat64.Dense, *mat64.Dense, err
 		return nil, nil, err
 	}
 
-	err = EigenRaw(inputPath, outputPath, mu, k)
+	err = run(inputPath, outputPath, mu, k)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -76,44 +150,45 @@ func Eigen(inputPath string, mu float64, k int) (*mat64.Dense, *mat64.Dense, err
 // 5 6
 //
 // Is written as:
-// 1 3 5 2 4 5
+// 1 3 5 2 4 6
 func ParseEigenOutput(path string) (*mat64.Dense, *mat64.Dense, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, nil, err
 	}
-	var qr, qc, zr, zc int
-	_, err = fmt.Fscanf(f, "%d %d ", &qr, &qc)
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	q, err := readColumnMajor(r)
 	if err != nil {
 		return nil, nil, err
 	}
-
-	qdata := make([]float64, qr*qc)
-	for i := 0; i < len(qdata); i++ {
-		var tmp float64
-		_, err = fmt.Fscanf(f, "%f ", &tmp)
-		fmt.Print(tmp, "\t")
-		qdata[i] = tmp
-		if err != nil {
-			return nil, nil, err
-		}
-	}
-
-	q := mat64.NewDense(qr, qc, qdata)
-
-	_, err = fmt.Fscanf(f, "%d %d ", &zr, &zc)
+	z, err := readColumnMajor(r)
 	if err != nil {
 		return nil, nil, err
 	}
+	return q, z, nil
+}
 
-	zdata := make([]float64, zr*zc)
-	for i := 0; i < len(zdata); i++ {
-		_, err = fmt.Fscanf(f, "%f ", &zdata[i])
-		if err != nil {
-			return nil, nil, err
+// Read the size of a matrix, then its elements column by column, as Matlab
+// writes them.
+func readColumnMajor(r io.Reader) (*mat64.Dense, error) {
+	var rows, cols int
+	if _, err := fmt.Fscan(r, &rows, &cols); err != nil {
+		return nil, err
+	}
+	if rows <= 0 || cols <= 0 {
+		return nil, fmt.Errorf("Bad matrix size %d x %d.", rows, cols)
+	}
+	m := mat64.NewDense(rows, cols, nil)
+	for j := 0; j < cols; j++ {
+		for i := 0; i < rows; i++ {
+			var v float64
+			if _, err := fmt.Fscan(r, &v); err != nil {
+				return nil, err
+			}
+			m.Set(i, j, v)
 		}
 	}
-
-	z := mat64.NewDense(zr, zc, zdata)
-	return q, z, nil
+	return m, nil
 }
@@ -1,5 +1,71 @@
 package sim
 
 import (
+	"io/ioutil"
+	"os"
 	"testing"
 )
+
+func TestNewBackend(t *testing.T) {
+	cases := map[string]EigenBackend{
+		"":            Native{},
+		BackendNative: Native{},
+		BackendMatlab: Matlab{Dir: "dir"},
+		BackendOctave: Octave{Dir: "dir"},
+	}
+	for name, expected := range cases {
+		b, err := NewBackend(Config{Backend: name, ScriptDir: "dir"})
+		if err != nil {
+			t.Errorf("%q: %s", name, err)
+			continue
+		}
+		if b != expected {
+			t.Errorf("%q: expected %#v, got %#v.", name, expected, b)
+		}
+	}
+
+	if _, err := NewBackend(Config{Backend: "nope"}); err == nil {
+		t.Error("Unknown backend did not return an error.")
+	}
+}
+
+func TestEigenRawMissingScript(t *testing.T) {
+	defer func(p, s string) { Path, ScriptName = p, s }(Path, ScriptName)
+	Path, ScriptName = "does-not-exist/", "missing.sh"
+
+	if err := EigenRaw("graph.csv", "out.txt", 0.5, 2); err == nil {
+		t.Error("Missing script did not return an error.")
+	}
+}
+
+func TestOctaveString(t *testing.T) {
+	if s := octaveString("it's"); s != "'it''s'" {
+		t.Errorf("Badly quoted: %s", s)
+	}
+}
+
+func TestParseEigenOutput(t *testing.T) {
+	f, err := ioutil.TempFile("", "eigen_output")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.WriteString("3 2\n1 3 5 2 4 6\n2 1\n7 8\n")
+	f.Close()
+
+	q, z, err := ParseEigenOutput(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedQ := [][]float64{{1, 2}, {3, 4}, {5, 6}}
+	for i, row := range expectedQ {
+		for j, v := range row {
+			if got := q.At(i, j); got != v {
+				t.Errorf("Q[%d][%d]: expected %v, got %v.", i, j, v, got)
+			}
+		}
+	}
+	if r, c := z.Dims(); r != 2 || c != 1 || z.At(0, 0) != 7 || z.At(1, 0) != 8 {
+		t.Errorf("Bad Z: %v", z)
+	}
+}
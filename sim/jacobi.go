@@ -0,0 +1,76 @@
+package sim
+
+import "math"
+
+// Maximum number of Jacobi sweeps before giving up on convergence.
+const jacobiMaxSweeps = 100
+
+// symEigen computes all the eigenvalues and eigenvectors of the symmetric
+// n x n matrix a (row-major), using the cyclic Jacobi method.
+// a is not modified.
+//
+// Returns the eigenvalues and the eigenvectors as the columns of a row-major
+// n x n matrix, in no particular order.
+func symEigen(a []float64, n int) ([]float64, []float64) {
+	m := make([]float64, len(a))
+	copy(m, a)
+
+	vecs := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		vecs[i*n+i] = 1
+	}
+
+	for sweep := 0; sweep < jacobiMaxSweeps; sweep++ {
+		var off, diag float64
+		for i := 0; i < n; i++ {
+			diag += m[i*n+i] * m[i*n+i]
+			for j := i + 1; j < n; j++ {
+				off += m[i*n+j] * m[i*n+j]
+			}
+		}
+		if off == 0 || off <= 1e-30*diag {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				apq := m[p*n+q]
+				if apq == 0 {
+					continue
+				}
+				app, aqq := m[p*n+p], m[q*n+q]
+
+				// rotation angle that zeroes m[p][q]
+				theta := (aqq - app) / (2 * apq)
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					mkp, mkq := m[k*n+p], m[k*n+q]
+					m[k*n+p] = c*mkp - s*mkq
+					m[k*n+q] = s*mkp + c*mkq
+				}
+				for k := 0; k < n; k++ {
+					mpk, mqk := m[p*n+k], m[q*n+k]
+					m[p*n+k] = c*mpk - s*mqk
+					m[q*n+k] = s*mpk + c*mqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := vecs[k*n+p], vecs[k*n+q]
+					vecs[k*n+p] = c*vkp - s*vkq
+					vecs[k*n+q] = s*vkp + c*vkq
+				}
+			}
+		}
+	}
+
+	vals := make([]float64, n)
+	for i := range vals {
+		vals[i] = m[i*n+i]
+	}
+	return vals, vecs
+}
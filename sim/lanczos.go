@@ -0,0 +1,363 @@
+package sim
+
+// Implicitly restarted Lanczos (Sorensen, 1992) for the leading eigenpairs of
+// a large sparse symmetric matrix. The Lanczos basis is fully
+// reorthogonalised, which costs O(n * Ncv) memory but keeps the method stable.
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/gonum/matrix/mat64"
+)
+
+// LanczosOptions controls the Lanczos eigensolver. Zero values pick defaults.
+type LanczosOptions struct {
+	// Tol is the relative residual at which a Ritz pair is accepted.
+	// Defaults to 1e-10.
+	Tol float64
+	// MaxIter is the maximum number of restarts. Defaults to 300, like eigs.
+	MaxIter int
+	// Ncv is the size of the Lanczos basis. Defaults to max(2k+1, 20).
+	Ncv int
+	// Seed for the random starting vector, so results are reproducible.
+	Seed int64
+}
+
+func (o LanczosOptions) withDefaults(n, k int) LanczosOptions {
+	if o.Tol <= 0 {
+		o.Tol = 1e-10
+	}
+	if o.MaxIter <= 0 {
+		o.MaxIter = 300
+	}
+	if o.Ncv <= k {
+		o.Ncv = 2*k + 1
+		if o.Ncv < 20 {
+			o.Ncv = 20
+		}
+	}
+	if o.Ncv > n {
+		o.Ncv = n
+	}
+	return o
+}
+
+// Lanczos computes the k eigenvalues of largest magnitude of the symmetric
+// operator a, largest first, and their eigenvectors as the columns of an
+// n x k matrix.
+//
+// Small problems (n no larger than the Lanczos basis) are solved densely.
+// Like any single-vector Lanczos method, it may return only one copy of a
+// repeated eigenvalue.
+func Lanczos(a Operator, k int, opts LanczosOptions) ([]float64, *mat64.Dense, error) {
+	n := a.Dim()
+	if k < 1 || k > n {
+		return nil, nil, fmt.Errorf("Cannot compute %d eigenvalues of a %dx%d matrix.", k, n, n)
+	}
+	opts = opts.withDefaults(n, k)
+	if opts.Ncv >= n {
+		return denseEigs(a, k)
+	}
+
+	l := &lanczos{
+		a:   a,
+		n:   n,
+		m:   opts.Ncv,
+		rnd: rand.New(rand.NewSource(opts.Seed)),
+	}
+	l.init()
+
+	l.extend(0)
+	for iter := 0; iter < opts.MaxIter; iter++ {
+		theta, s := symEigen(l.t, l.m)
+		order := largestMagnitude(theta, len(theta))
+
+		if l.converged(theta, s, order[:k], opts.Tol) {
+			return l.ritz(theta, s, order[:k])
+		}
+
+		// exact shifts: the unwanted Ritz values
+		shifts := make([]float64, 0, l.m-k)
+		for _, i := range order[k:] {
+			shifts = append(shifts, theta[i])
+		}
+		l.restart(k, shifts)
+		l.extend(k)
+	}
+
+	return nil, nil, fmt.Errorf("Lanczos did not converge in %d restarts.", opts.MaxIter)
+}
+
+// Solve a small problem by building the dense matrix column by column.
+func denseEigs(a Operator, k int) ([]float64, *mat64.Dense, error) {
+	n := a.Dim()
+	dense := make([]float64, n*n)
+	x := make([]float64, n)
+	col := make([]float64, n)
+	for j := 0; j < n; j++ {
+		x[j] = 1
+		a.MulVec(col, x)
+		x[j] = 0
+		for i := 0; i < n; i++ {
+			dense[i*n+j] = col[i]
+		}
+	}
+
+	vals, vecs := symEigen(dense, n)
+	order := largestMagnitude(vals, k)
+
+	resVals := make([]float64, k)
+	resVecs := mat64.NewDense(n, k, nil)
+	for j, c := range order {
+		resVals[j] = vals[c]
+		for i := 0; i < n; i++ {
+			resVecs.Set(i, j, vecs[i*n+c])
+		}
+	}
+	return resVals, resVecs, nil
+}
+
+// State of the Lanczos factorisation A V = V T + f e_m'.
+type lanczos struct {
+	a   Operator
+	n   int // size of the problem
+	m   int // size of the basis
+	rnd *rand.Rand
+
+	v [][]float64 // basis vectors, m of them
+	t []float64   // m x m projected matrix, row-major (tridiagonal)
+	f []float64   // residual vector
+	w []float64   // scratch
+}
+
+func (l *lanczos) init() {
+	l.v = make([][]float64, l.m)
+	for i := range l.v {
+		l.v[i] = make([]float64, l.n)
+	}
+	l.t = make([]float64, l.m*l.m)
+	l.f = make([]float64, l.n)
+	l.w = make([]float64, l.n)
+}
+
+// Extend a factorisation of size j0 to the full basis size m.
+func (l *lanczos) extend(j0 int) {
+	m := l.m
+	for j := j0; j < m; j++ {
+		beta := 0.0
+		if j > 0 {
+			beta = norm(l.f)
+		}
+		if j == 0 || beta < 1e-14 {
+			// start, or the Krylov space became invariant: carry on with a
+			// random vector orthogonal to the current basis.
+			l.randomOrthogonal(l.v[j], j)
+			beta = 0
+		} else {
+			for i := range l.f {
+				l.v[j][i] = l.f[i] / beta
+			}
+		}
+		if j > 0 {
+			l.t[j*m+j-1] = beta
+			l.t[(j-1)*m+j] = beta
+		}
+
+		l.a.MulVec(l.w, l.v[j])
+		if j > 0 {
+			axpy(-beta, l.v[j-1], l.w)
+		}
+		alpha := dot(l.v[j], l.w)
+		l.t[j*m+j] = alpha
+		axpy(-alpha, l.v[j], l.w)
+
+		l.orthogonalise(l.w, j+1)
+		copy(l.f, l.w)
+	}
+}
+
+// Fill x with a random unit vector orthogonal to the first j basis vectors.
+func (l *lanczos) randomOrthogonal(x []float64, j int) {
+	for {
+		for i := range x {
+			x[i] = l.rnd.Float64() - 0.5
+		}
+		l.orthogonalise(x, j)
+		if nrm := norm(x); nrm > 1e-8 {
+			for i := range x {
+				x[i] /= nrm
+			}
+			return
+		}
+	}
+}
+
+// Classical Gram-Schmidt against the first j basis vectors, done twice.
+func (l *lanczos) orthogonalise(x []float64, j int) {
+	for pass := 0; pass < 2; pass++ {
+		for i := 0; i < j; i++ {
+			axpy(-dot(l.v[i], x), l.v[i], x)
+		}
+	}
+}
+
+// Check the residuals of the wanted Ritz pairs: |beta_m * s(m, i)|.
+func (l *lanczos) converged(theta, s []float64, wanted []int, tol float64) bool {
+	beta := norm(l.f)
+	for _, i := range wanted {
+		limit := tol * math.Max(math.Abs(theta[i]), 1e-10)
+		if math.Abs(beta*s[(l.m-1)*l.m+i]) > limit {
+			return false
+		}
+	}
+	return true
+}
+
+// Ritz vectors V * s(:, i) for the wanted Ritz values.
+func (l *lanczos) ritz(theta, s []float64, wanted []int) ([]float64, *mat64.Dense, error) {
+	vals := make([]float64, len(wanted))
+	vecs := mat64.NewDense(l.n, len(wanted), nil)
+	for c, i := range wanted {
+		vals[c] = theta[i]
+		for r := 0; r < l.n; r++ {
+			var sum float64
+			for j := 0; j < l.m; j++ {
+				sum += l.v[j][r] * s[j*l.m+i]
+			}
+			vecs.Set(r, c, sum)
+		}
+	}
+	return vals, vecs, nil
+}
+
+// Apply the shifts with implicit QR steps on T, then truncate the
+// factorisation to size k.
+func (l *lanczos) restart(k int, shifts []float64) {
+	m := l.m
+	q := identity(m)
+	for _, mu := range shifts {
+		qj := shiftedQRStep(l.t, m, mu)
+		q = matMul(q, qj, m)
+	}
+
+	// V+ = V Q, only the first k+1 columns are needed
+	newV := make([][]float64, k+1)
+	for c := 0; c <= k; c++ {
+		newV[c] = make([]float64, l.n)
+		for j := 0; j < m; j++ {
+			if qjc := q[j*m+c]; qjc != 0 {
+				axpy(qjc, l.v[j], newV[c])
+			}
+		}
+	}
+
+	// f+ = V+(:,k+1) * T(k+1,k) + f * Q(m,k)
+	betaK := l.t[k*m+k-1]
+	sigmaK := q[(m-1)*m+k-1]
+	for i := range l.f {
+		l.f[i] = newV[k][i]*betaK + l.f[i]*sigmaK
+	}
+	for c := 0; c < k; c++ {
+		copy(l.v[c], newV[c])
+	}
+
+	// keep the leading k x k block of T, and make sure it stays tridiagonal
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			if i >= k || j >= k || i-j > 1 || j-i > 1 {
+				l.t[i*m+j] = 0
+			}
+		}
+	}
+	for i := 0; i+1 < k; i++ {
+		avg := (l.t[(i+1)*m+i] + l.t[i*m+i+1]) / 2
+		l.t[(i+1)*m+i] = avg
+		l.t[i*m+i+1] = avg
+	}
+}
+
+// One QR step with shift mu on the m x m matrix t, in place:
+// t - mu I = QR, t = RQ + mu I. Returns Q.
+func shiftedQRStep(t []float64, m int, mu float64) []float64 {
+	r := make([]float64, len(t))
+	copy(r, t)
+	for i := 0; i < m; i++ {
+		r[i*m+i] -= mu
+	}
+	q := identity(m)
+
+	// Givens rotations zeroing the sub-diagonal
+	for j := 0; j+1 < m; j++ {
+		a, b := r[j*m+j], r[(j+1)*m+j]
+		if b == 0 {
+			continue
+		}
+		h := math.Hypot(a, b)
+		c, s := a/h, b/h
+		for col := 0; col < m; col++ {
+			x, y := r[j*m+col], r[(j+1)*m+col]
+			r[j*m+col] = c*x + s*y
+			r[(j+1)*m+col] = -s*x + c*y
+		}
+		for row := 0; row < m; row++ {
+			x, y := q[row*m+j], q[row*m+j+1]
+			q[row*m+j] = c*x + s*y
+			q[row*m+j+1] = -s*x + c*y
+		}
+	}
+
+	rq := matMul(r, q, m)
+	for i := 0; i < m; i++ {
+		for j := 0; j < m; j++ {
+			t[i*m+j] = rq[i*m+j]
+		}
+		t[i*m+i] += mu
+	}
+	return q
+}
+
+func identity(m int) []float64 {
+	res := make([]float64, m*m)
+	for i := 0; i < m; i++ {
+		res[i*m+i] = 1
+	}
+	return res
+}
+
+// Product of two m x m row-major matrices.
+func matMul(a, b []float64, m int) []float64 {
+	res := make([]float64, m*m)
+	for i := 0; i < m; i++ {
+		for k := 0; k < m; k++ {
+			aik := a[i*m+k]
+			if aik == 0 {
+				continue
+			}
+			for j := 0; j < m; j++ {
+				res[i*m+j] += aik * b[k*m+j]
+			}
+		}
+	}
+	return res
+}
+
+func dot(x, y []float64) float64 {
+	var sum float64
+	for i := range x {
+		sum += x[i] * y[i]
+	}
+	return sum
+}
+
+func norm(x []float64) float64 {
+	return math.Sqrt(dot(x, x))
+}
+
+// y += alpha * x
+func axpy(alpha float64, x, y []float64) {
+	for i := range x {
+		y[i] += alpha * x[i]
+	}
+}
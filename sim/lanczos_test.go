@@ -0,0 +1,211 @@
+package sim
+
+import (
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/vladvelici/graph-dataset-tools/graph"
+	"github.com/vladvelici/graph-dataset-tools/util"
+)
+
+// A random sparse symmetric matrix: a ring plus random weighted chords.
+func randomSymmetric(n, chords int, seed int64) *Sparse {
+	rnd := rand.New(rand.NewSource(seed))
+	var rows, cols []int
+	var vals []float64
+	add := func(a, b int, w float64) {
+		rows = append(rows, a, b)
+		cols = append(cols, b, a)
+		vals = append(vals, w, w)
+	}
+	for i := 0; i < n; i++ {
+		add(i, (i+1)%n, rnd.Float64())
+	}
+	for i := 0; i < chords; i++ {
+		add(rnd.Intn(n), rnd.Intn(n), rnd.Float64())
+	}
+	return NewSparse(n, rows, cols, vals)
+}
+
+func TestSparseDuplicates(t *testing.T) {
+	s := NewSparse(2, []int{0, 1, 0}, []int{1, 0, 1}, []float64{1, 2, 3})
+	dst := make([]float64, 2)
+	s.MulVec(dst, []float64{1, 10})
+	if dst[0] != 40 || dst[1] != 2 {
+		t.Errorf("Expected (40, 2), got %v.", dst)
+	}
+}
+
+func TestLanczosMatchesDense(t *testing.T) {
+	n, k := 300, 6
+	a := randomSymmetric(n, 600, 42)
+
+	vals, vecs, err := Lanczos(a, k, LanczosOptions{Seed: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _, err := denseEigs(a, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < k; i++ {
+		if math.Abs(vals[i]-expected[i]) > 1e-8 {
+			t.Errorf("Eigenvalue %d: %f, dense solver says %f.", i, vals[i], expected[i])
+		}
+	}
+
+	// residual ||A v - lambda v||
+	v := make([]float64, n)
+	av := make([]float64, n)
+	for c := 0; c < k; c++ {
+		for i := range v {
+			v[i] = vecs.At(i, c)
+		}
+		a.MulVec(av, v)
+		axpy(-vals[c], v, av)
+		if r := norm(av); r > 1e-6 {
+			t.Errorf("Eigenpair %d has residual %g.", c, r)
+		}
+	}
+}
+
+func TestLanczosSeed(t *testing.T) {
+	a := randomSymmetric(200, 300, 3)
+	opts := LanczosOptions{Seed: 11}
+
+	_, v1, err := Lanczos(a, 4, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, v2, err := Lanczos(a, 4, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 200; i++ {
+		for j := 0; j < 4; j++ {
+			if v1.At(i, j) != v2.At(i, j) {
+				t.Fatalf("Same seed gave different vectors at (%d, %d).", i, j)
+			}
+		}
+	}
+}
+
+func TestLanczosMaxIter(t *testing.T) {
+	a := randomSymmetric(200, 300, 3)
+	_, _, err := Lanczos(a, 4, LanczosOptions{Tol: 1e-300, MaxIter: 1})
+	if err == nil {
+		t.Error("Expected a convergence error with an impossible tolerance.")
+	}
+}
+
+// The normalised adjacency of a path has eigenvalues cos(pi j / (n-1)).
+func TestLanczosPath(t *testing.T) {
+	n := 101
+	var rows, cols []int
+	var vals []float64
+	for i := 0; i+1 < n; i++ {
+		rows = append(rows, i, i+1)
+		cols = append(cols, i+1, i)
+		vals = append(vals, 1, 1)
+	}
+	adj := NewSparse(n, rows, cols, vals)
+	d := adj.RowSums()
+	for i := range d {
+		d[i] = 1 / math.Sqrt(d[i])
+	}
+
+	got, _, err := Lanczos(adj.Scale(d), 4, LanczosOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := make([]float64, n)
+	for j := range expected {
+		expected[j] = math.Cos(math.Pi * float64(j) / float64(n-1))
+	}
+	sort.Slice(expected, func(i, j int) bool { return math.Abs(expected[i]) > math.Abs(expected[j]) })
+	for i := range got {
+		if math.Abs(math.Abs(got[i])-math.Abs(expected[i])) > 1e-8 {
+			t.Errorf("Eigenvalue %d: |%f|, expected |%f|.", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestReadAdjacencyWeights(t *testing.T) {
+	f, err := ioutil.TempFile("", "adj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	// 2,3 is repeated: the last weight is kept, not the sum
+	f.WriteString("1,2,0.5\n2,1,0.5\n2,3,4\n3,2,1\n2,3,1\n")
+	f.Close()
+
+	adj, err := readAdjacency(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sums := adj.RowSums()
+	if sums[0] != 0.5 || sums[1] != 1.5 || sums[2] != 1 {
+		t.Errorf("Expected weighted degrees (0.5, 1.5, 1), got %v.", sums)
+	}
+}
+
+func TestReadBinAdjacency(t *testing.T) {
+	b := util.NewBinGraphBuilder()
+	edges := [][3]float64{{1, 2, 0.5}, {2, 1, 0.5}, {2, 4, 1}, {4, 2, 1}, {4, 4, 2}}
+	for _, e := range edges {
+		b.Add(int(e[0]), int(e[1]), e[2])
+	}
+	g, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := ioutil.TempFile("", "adj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if err := util.WriteBinGraph(f, g); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	adj, err := readAdjacency(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := NewSparse(4, []int{0, 1, 1, 3, 3}, []int{1, 0, 3, 1, 3}, []float64{0.5, 0.5, 1, 1, 2})
+	x := []float64{1, 2, 3, 4}
+	got, want := make([]float64, 4), make([]float64, 4)
+	adj.MulVec(got, x)
+	expected.MulVec(want, x)
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("Row %d: expected %v, got %v.", i, want[i], got[i])
+		}
+	}
+}
+
+func TestAdjacency(t *testing.T) {
+	b := graph.NewBuilder()
+	b.AddWeightedEdge(1, 3, 2)
+	b.AddEdge(3, 4)
+	adj, err := Adjacency(b.Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if adj.Dim() != 4 {
+		t.Fatalf("Expected a 4x4 matrix, got %d.", adj.Dim())
+	}
+	sums := adj.RowSums()
+	if sums[0] != 2 || sums[1] != 0 || sums[2] != 3 || sums[3] != 1 {
+		t.Errorf("Expected weighted degrees (2, 0, 3, 1), got %v.", sums)
+	}
+}
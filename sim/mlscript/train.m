@@ -13,7 +13,16 @@ function [ q,z ] = train( csv_path, mu, k, output_path )
     k = str2double(k);
    
     raw = csvread(csv_path);
-    adj = sparse(raw(:,1), raw(:,2), ones(size(raw,1),1));
+    % repeated edges are kept once, with the weight of the last one
+    [~, last] = unique(raw(:,1:2), 'rows', 'last');
+    raw = raw(sort(last),:);
+    if size(raw,2) >= 3
+        % third column is the edge weight
+        weights = raw(:,3);
+    else
+        weights = ones(size(raw,1),1);
+    end
+    adj = sparse(raw(:,1), raw(:,2), weights);
     [q, z] = similarity(adj, mu, int64(k));
 
     of = fopen(output_path, 'w');
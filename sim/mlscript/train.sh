@@ -1,7 +1,7 @@
 #!/bin/bash
 
-## Set matlab path to this variable
-MATLAB_PATH=/Applications/MATLAB_R2014b.app/bin/matlab
+## Set matlab path to this variable (or export MATLAB_PATH before running)
+MATLAB_PATH=${MATLAB_PATH:-/Applications/MATLAB_R2014b.app/bin/matlab}
 
 MATLAB_OPTIONS="-nodisplay -nojvm -r"
 
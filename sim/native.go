@@ -0,0 +1,158 @@
+package sim
+
+// This file is a Go port of mlscript/similarity.m, so that Q and Z can be
+// computed without a Matlab installation.
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/gonum/matrix/mat64"
+	"github.com/vladvelici/graph-dataset-tools/graph"
+)
+
+// EigenNative reads the edge list at inputPath and computes Q and Z in Go,
+// with the default LanczosOptions.
+// Like train.m, node IDs in the file start from 1 and repeated edges are
+// kept once, with the weight of the last one.
+func EigenNative(inputPath string, mu float64, k int) (*mat64.Dense, *mat64.Dense, error) {
+	return EigenNativeOptions(inputPath, mu, k, LanczosOptions{})
+}
+
+// EigenNativeOptions is EigenNative with control over the eigensolver.
+func EigenNativeOptions(inputPath string, mu float64, k int, opts LanczosOptions) (*mat64.Dense, *mat64.Dense, error) {
+	adj, err := readAdjacency(inputPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return similarity(adj, mu, k, opts)
+}
+
+// Read an edge list (any format of util.OpenEdges) into a sparse adjacency
+// matrix. Its size is the largest node ID found in the file. A third column
+// is the edge weight, 1 if missing. Like util.BinGraphBuilder, repeated
+// edges are kept once, with the weight of the last one.
+func readAdjacency(path string) (*Sparse, error) {
+	g, err := graph.Read(path)
+	if err != nil {
+		return nil, err
+	}
+	return Adjacency(g)
+}
+
+// EigenGraph computes Q and Z for the undirected graph g, whose node IDs
+// start from 1, with the weights of g if it is graph.Weighted.
+// Row i of Z is the node with ID i+1.
+func EigenGraph(g graph.Graph, mu float64, k int, opts LanczosOptions) (*mat64.Dense, *mat64.Dense, error) {
+	adj, err := Adjacency(g)
+	if err != nil {
+		return nil, nil, err
+	}
+	return similarity(adj, mu, k, opts)
+}
+
+// Adjacency builds the sparse adjacency matrix of g, whose node IDs start
+// from 1: node ID i is row i-1. Its size is the largest node ID.
+// Rows of missing IDs are empty.
+func Adjacency(g graph.Graph) (*Sparse, error) {
+	ids := g.Nodes()
+	var n int
+	if len(ids) > 0 {
+		if ids[0] < 1 {
+			return nil, fmt.Errorf("Node IDs must start from 1, found node %d.", ids[0])
+		}
+		n = ids[len(ids)-1]
+	}
+
+	// nodes and their neighbours are sorted, so rows are already in order
+	s := &Sparse{
+		n:       n,
+		offsets: make([]int, n+1),
+	}
+	for _, id := range ids {
+		for _, to := range g.Neighbours(id) {
+			s.cols = append(s.cols, to-1)
+			s.vals = append(s.vals, graph.WeightOf(g, id, to))
+		}
+		s.offsets[id] = len(s.cols)
+	}
+	// row id-1 ends at offsets[id]; empty rows end where the previous did
+	for r := 1; r <= n; r++ {
+		if s.offsets[r] < s.offsets[r-1] {
+			s.offsets[r] = s.offsets[r-1]
+		}
+	}
+	return s, nil
+}
+
+// similarity computes Q and Z for the (undirected, so symmetric) adjacency
+// matrix adj, using the k eigenvalues of largest magnitude.
+//
+// Nodes without any edges would divide by zero in the Matlab code; here they
+// are left with zero rows in Z.
+func similarity(adj *Sparse, mu float64, k int, opts LanczosOptions) (*mat64.Dense, *mat64.Dense, error) {
+	n := adj.Dim()
+	if k < 1 || k > n {
+		return nil, nil, fmt.Errorf("Cannot use %d eigenvalues for a graph with %d nodes.", k, n)
+	}
+
+	// neigh = sum(adj,2); neighinv = neigh.^-1;
+	neigh := adj.RowSums()
+	neighinv := make([]float64, n)
+	wHalf := make([]float64, n)
+	for i := 0; i < n; i++ {
+		if neigh[i] != 0 {
+			neighinv[i] = 1 / neigh[i]
+			wHalf[i] = math.Sqrt(neighinv[i])
+		}
+	}
+
+	// A = wHalf * adj * wHalf;
+	// [vec, val] = eigs(A,[],m);
+	vals, vecs, err := Lanczos(adj.Scale(wHalf), k, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// gamma(i,i) = (1-mu*val(i,i))^-1;
+	gamma := make([]float64, k)
+	for j := range gamma {
+		gamma[j] = 1 / (1 - mu*vals[j])
+	}
+
+	// z = diag(sqrt(neigh)) * vec * gamma;
+	z := mat64.NewDense(n, k, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < k; j++ {
+			z.Set(i, j, math.Sqrt(neigh[i])*vecs.At(i, j)*gamma[j])
+		}
+	}
+
+	// q = vec' * w * vec;
+	q := mat64.NewDense(k, k, nil)
+	for r := 0; r < k; r++ {
+		for c := 0; c < k; c++ {
+			var sum float64
+			for i := 0; i < n; i++ {
+				sum += vecs.At(i, r) * neighinv[i] * vecs.At(i, c)
+			}
+			q.Set(r, c, sum)
+		}
+	}
+
+	return q, z, nil
+}
+
+// Indices of the k values with the largest magnitude, largest first.
+// This is the default ordering of Matlab's eigs.
+func largestMagnitude(vals []float64, k int) []int {
+	order := make([]int, len(vals))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return math.Abs(vals[order[i]]) > math.Abs(vals[order[j]])
+	})
+	return order[:k]
+}
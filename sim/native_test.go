@@ -0,0 +1,118 @@
+package sim
+
+import (
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+const epsilon = 1e-9
+
+func TestSymEigen(t *testing.T) {
+	n := 6
+	rnd := rand.New(rand.NewSource(1))
+	a := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			a[i*n+j] = rnd.Float64()
+			a[j*n+i] = a[i*n+j]
+		}
+	}
+
+	vals, vecs := symEigen(a, n)
+
+	// A v = lambda v for every pair
+	for c := 0; c < n; c++ {
+		for i := 0; i < n; i++ {
+			var av float64
+			for j := 0; j < n; j++ {
+				av += a[i*n+j] * vecs[j*n+c]
+			}
+			if math.Abs(av-vals[c]*vecs[i*n+c]) > epsilon {
+				t.Errorf("Eigenpair %d: (Av)_%d = %f, expected %f.", c, i, av, vals[c]*vecs[i*n+c])
+			}
+		}
+	}
+
+	// vectors are orthonormal
+	for c := 0; c < n; c++ {
+		for d := 0; d < n; d++ {
+			var dot float64
+			for i := 0; i < n; i++ {
+				dot += vecs[i*n+c] * vecs[i*n+d]
+			}
+			expected := 0.0
+			if c == d {
+				expected = 1
+			}
+			if math.Abs(dot-expected) > epsilon {
+				t.Errorf("v%d . v%d = %f, expected %f.", c, d, dot, expected)
+			}
+		}
+	}
+}
+
+func TestLargestMagnitude(t *testing.T) {
+	order := largestMagnitude([]float64{0.5, -2, 1, 0}, 3)
+	expected := []int{1, 2, 0}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Errorf("Expected order %v, got %v.", expected, order)
+			break
+		}
+	}
+}
+
+func writeTemp(t *testing.T, content string) string {
+	file, err := ioutil.TempFile("", "sim_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err = file.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+	return file.Name()
+}
+
+// A triangle has eigenvalues 1, -1/2, -1/2 and leading vector (1,1,1)/sqrt(3).
+func TestEigenNativeTriangle(t *testing.T) {
+	path := writeTemp(t, "1, 2\n2, 1\n2, 3\n3, 2\n1, 3\n3, 1\n")
+	defer os.Remove(path)
+
+	q, z, err := EigenNative(path, 0.5, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, c := q.Dims(); r != 1 || c != 1 {
+		t.Fatalf("Q should be 1x1, is %dx%d.", r, c)
+	}
+	if r, c := z.Dims(); r != 3 || c != 1 {
+		t.Fatalf("Z should be 3x1, is %dx%d.", r, c)
+	}
+
+	// q = sum(v_i^2 / deg_i) = 1/2
+	if math.Abs(q.At(0, 0)-0.5) > epsilon {
+		t.Errorf("Q(0,0) = %f, expected 0.5.", q.At(0, 0))
+	}
+
+	// z_i = sqrt(2) * 1/sqrt(3) * 1/(1-0.5*1)
+	expected := math.Sqrt(2) / math.Sqrt(3) * 2
+	for i := 0; i < 3; i++ {
+		if math.Abs(math.Abs(z.At(i, 0))-expected) > epsilon {
+			t.Errorf("|Z(%d,0)| = %f, expected %f.", i, math.Abs(z.At(i, 0)), expected)
+		}
+	}
+}
+
+func TestEigenNativeTooManyEigenvalues(t *testing.T) {
+	path := writeTemp(t, "1, 2\n2, 1\n")
+	defer os.Remove(path)
+
+	if _, _, err := EigenNative(path, 0.5, 3); err == nil {
+		t.Error("Asking for 3 eigenvalues of a 2 node graph should fail.")
+	}
+}
@@ -0,0 +1,110 @@
+package sim
+
+import (
+	"container/heap"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// Neighbour is a node and its DistanceSim to a query node.
+type Neighbour struct {
+	Node  int
+	Score float64
+}
+
+// Ordering of neighbours: most similar (smallest distance) first, ties by node ID.
+func closer(a, b Neighbour) bool {
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	return a.Node < b.Node
+}
+
+// Max-heap of neighbours, the least similar at the top.
+type neighbourHeap []Neighbour
+
+func (h neighbourHeap) Len() int            { return len(h) }
+func (h neighbourHeap) Less(i, j int) bool  { return closer(h[j], h[i]) }
+func (h neighbourHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *neighbourHeap) Push(x interface{}) { *h = append(*h, x.(Neighbour)) }
+func (h *neighbourHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// Keep the n most similar neighbours offered to it.
+type topN struct {
+	n int
+	h neighbourHeap
+}
+
+// Keeps nothing for n <= 0.
+func newTopN(n int) *topN {
+	if n < 0 {
+		n = 0
+	}
+	return &topN{n, make(neighbourHeap, 0, n)}
+}
+
+func (t *topN) offer(nb Neighbour) {
+	if len(t.h) < t.n {
+		heap.Push(&t.h, nb)
+	} else if t.n > 0 && closer(nb, t.h[0]) {
+		t.h[0] = nb
+		heap.Fix(&t.h, 0)
+	}
+}
+
+// The kept neighbours, most similar first.
+func (t *topN) sorted() []Neighbour {
+	res := []Neighbour(t.h)
+	sort.Slice(res, func(i, j int) bool { return closer(res[i], res[j]) })
+	return res
+}
+
+// Nearest returns the n nodes most similar to node (by DistanceSim), most
+// similar first. The node itself is not included. Returns nothing for n <= 0.
+func (r *Result) Nearest(node, n int) []Neighbour {
+	r.prepare()
+
+	top := newTopN(n)
+	for other := 0; other < len(r.norms); other++ {
+		if other == node {
+			continue
+		}
+		top.offer(Neighbour{other, r.norms[node] + r.norms[other] - 2*r.cross(node, other)})
+	}
+	return top.sorted()
+}
+
+// NearestBatch answers Nearest(node, n) for each of nodes, using workers
+// goroutines (GOMAXPROCS if workers <= 0). The i-th result is for nodes[i].
+func (r *Result) NearestBatch(nodes []int, n, workers int) [][]Neighbour {
+	r.prepare()
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	res := make([][]Neighbour, len(nodes))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res[i] = r.Nearest(nodes[i], n)
+			}
+		}()
+	}
+	for i := range nodes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return res
+}
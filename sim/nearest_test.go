@@ -0,0 +1,81 @@
+package sim
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gonum/matrix/mat64"
+)
+
+// With Q = I, DistanceSim is the squared euclidean distance between rows of Z.
+func pointsResult() *Result {
+	q := mat64.NewDense(2, 2, []float64{1, 0, 0, 1})
+	z := mat64.NewDense(5, 2, []float64{
+		0, 0, // 0
+		1, 0, // 1
+		0, 2, // 2
+		3, 3, // 3
+		-1, 0, // 4
+	})
+	return FromQZ(q, z)
+}
+
+func TestDistanceSim(t *testing.T) {
+	r := pointsResult()
+	cases := []struct {
+		a, b     int
+		expected float64
+	}{
+		{0, 1, 1},
+		{1, 2, 5},
+		{3, 4, 25},
+		{2, 2, 0},
+	}
+	for _, c := range cases {
+		if d := r.DistanceSim(c.a, c.b); math.Abs(d-c.expected) > epsilon {
+			t.Errorf("DistanceSim(%d, %d) = %f, expected %f.", c.a, c.b, d, c.expected)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	r := pointsResult()
+
+	// from node 0: 1 and 4 at 1 (tie, by ID), 2 at 4, 3 at 18
+	expected := []Neighbour{{1, 1}, {4, 1}, {2, 4}}
+	got := r.Nearest(0, 3)
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %d neighbours, got %d.", len(expected), len(got))
+	}
+	for i := range expected {
+		if got[i].Node != expected[i].Node || math.Abs(got[i].Score-expected[i].Score) > epsilon {
+			t.Errorf("Neighbour %d: expected %v, got %v.", i, expected[i], got[i])
+		}
+	}
+
+	if all := r.Nearest(0, 100); len(all) != 4 {
+		t.Errorf("Asking for too many neighbours should return all 4 others, got %d.", len(all))
+	}
+	if none := r.Nearest(0, -1); len(none) != 0 {
+		t.Errorf("Asking for -1 neighbours should return none, got %d.", len(none))
+	}
+}
+
+func TestNearestBatch(t *testing.T) {
+	r := pointsResult()
+	nodes := []int{4, 0, 3, 1, 2, 0}
+
+	batch := r.NearestBatch(nodes, 2, 3)
+	for i, node := range nodes {
+		single := r.Nearest(node, 2)
+		if len(batch[i]) != len(single) {
+			t.Fatalf("Query %d: batch has %d results, single %d.", i, len(batch[i]), len(single))
+		}
+		for j := range single {
+			if batch[i][j] != single[j] {
+				t.Errorf("Query %d: batch %v, single %v.", i, batch[i], single)
+				break
+			}
+		}
+	}
+}
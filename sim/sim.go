@@ -1,7 +1,7 @@
 package sim
 
 import (
-	"fmt"
+	"sync"
 
 	"github.com/gonum/matrix/mat64"
 )
@@ -11,6 +11,13 @@ import (
 type Result struct {
 	q *mat64.Dense
 	z *mat64.Dense
+
+	// Computed once, on first use. See prepare.
+	once  sync.Once
+	k     int
+	zraw  []float64 // Z, row-major
+	zq    []float64 // Z * Q, row-major
+	norms []float64 // z_i * Q * z_i'
 }
 
 // FromQZ creates a result object form a q and a z matrix.
@@ -28,36 +35,61 @@ func (r *Result) Len() int {
 	return rows
 }
 
-func printdim(a mat64.Matrix, name string) {
-	rows, cols := a.Dims()
-	fmt.Printf("%s (%d, %d)\n", name, rows, cols)
+// Precompute Z*Q and the per-node norms z_i*Q*z_i', so every similarity
+// afterwards is a single dot product. Safe for concurrent use.
+func (r *Result) prepare() {
+	r.once.Do(func() {
+		n, k := r.z.Dims()
+		r.k = k
+		r.zraw = make([]float64, n*k)
+		for i := 0; i < n; i++ {
+			for j := 0; j < k; j++ {
+				r.zraw[i*k+j] = r.z.At(i, j)
+			}
+		}
+
+		q := make([]float64, k*k)
+		for i := 0; i < k; i++ {
+			for j := 0; j < k; j++ {
+				q[i*k+j] = r.q.At(i, j)
+			}
+		}
+
+		r.zq = make([]float64, n*k)
+		r.norms = make([]float64, n)
+		for i := 0; i < n; i++ {
+			row := r.zraw[i*k : (i+1)*k]
+			out := r.zq[i*k : (i+1)*k]
+			for a, za := range row {
+				if za == 0 {
+					continue
+				}
+				for b := 0; b < k; b++ {
+					out[b] += za * q[a*k+b]
+				}
+			}
+			r.norms[i] = dot(out, row)
+		}
+	})
 }
 
-// Multiplies a * m * b'.
-func multipl(a *mat64.Vector, m mat64.Matrix, b *mat64.Vector) float64 {
-	printdim(a, "a")
-	printdim(m, "m")
-	printdim(b, "b")
-	p1 := new(mat64.Dense)
-	p1.MulTrans(a, true, m, false)
-	res := new(mat64.Dense)
-	printdim(p1, "p1")
-	res.Mul(p1, b)
-	return res.At(0, 0)
+// z_from * Q * z_to', once prepared.
+func (r *Result) cross(from, to int) float64 {
+	k := r.k
+	return dot(r.zq[from*k:(from+1)*k], r.zraw[to*k:(to+1)*k])
 }
 
 // Sim returns the similarity metric between two nodes.
+// It is a distance: the smaller, the more similar the nodes are.
 func (r *Result) DistanceSim(from, to int) float64 {
-	fromRow := r.z.RowView(from)
-	toRow := r.z.RowView(to)
+	r.prepare()
 
 	// matlab code to port:
 	// norma = z(a,:)*q*z(a,:)';
 	// normb = z(b,:)*q*z(b,:)';
 	// similarity = norma + normb - 2 * (z(a,:)*q*z(b,:)');
 
-	similarity := multipl(fromRow, r.q, fromRow) + multipl(toRow, r.q, toRow) + 2*multipl(fromRow, r.q, toRow)
-	return similarity
+	return r.norms[from] + r.norms[to] - 2*r.cross(from, to)
 }
 
 // Could have more similarity measures here...
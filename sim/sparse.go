@@ -0,0 +1,102 @@
+package sim
+
+import "sort"
+
+// Operator is a square linear operator, only accessed through
+// matrix-vector products. This is all the Lanczos solver needs.
+type Operator interface {
+	// Dim returns n, for an n x n operator.
+	Dim() int
+	// MulVec sets dst = A * x. dst and x have length Dim() and do not overlap.
+	MulVec(dst, x []float64)
+}
+
+// Sparse is a square matrix in compressed sparse row (CSR) form.
+type Sparse struct {
+	n       int
+	offsets []int // row i is cols[offsets[i]:offsets[i+1]]
+	cols    []int
+	vals    []float64
+}
+
+// NewSparse builds an n x n sparse matrix from (row, col, value) triplets.
+// Like Matlab's sparse(), repeated (row, col) entries are added together.
+func NewSparse(n int, rows, cols []int, vals []float64) *Sparse {
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		a, b := order[i], order[j]
+		if rows[a] != rows[b] {
+			return rows[a] < rows[b]
+		}
+		return cols[a] < cols[b]
+	})
+
+	s := &Sparse{
+		n:       n,
+		offsets: make([]int, n+1),
+		cols:    make([]int, 0, len(order)),
+		vals:    make([]float64, 0, len(order)),
+	}
+	last := -1
+	for _, i := range order {
+		if last >= 0 && rows[i] == rows[last] && cols[i] == cols[last] {
+			s.vals[len(s.vals)-1] += vals[i]
+			continue
+		}
+		s.cols = append(s.cols, cols[i])
+		s.vals = append(s.vals, vals[i])
+		s.offsets[rows[i]+1]++
+		last = i
+	}
+	for i := 0; i < n; i++ {
+		s.offsets[i+1] += s.offsets[i]
+	}
+	return s
+}
+
+// Dim returns the number of rows (and columns) of the matrix.
+func (s *Sparse) Dim() int {
+	return s.n
+}
+
+// MulVec sets dst = s * x.
+func (s *Sparse) MulVec(dst, x []float64) {
+	for i := 0; i < s.n; i++ {
+		var sum float64
+		for p := s.offsets[i]; p < s.offsets[i+1]; p++ {
+			sum += s.vals[p] * x[s.cols[p]]
+		}
+		dst[i] = sum
+	}
+}
+
+// RowSums returns the sum of each row, i.e. the (weighted) degrees
+// when s is an adjacency matrix.
+func (s *Sparse) RowSums() []float64 {
+	sums := make([]float64, s.n)
+	for i := 0; i < s.n; i++ {
+		for p := s.offsets[i]; p < s.offsets[i+1]; p++ {
+			sums[i] += s.vals[p]
+		}
+	}
+	return sums
+}
+
+// Scale returns diag(d) * s * diag(d), leaving s untouched.
+func (s *Sparse) Scale(d []float64) *Sparse {
+	res := &Sparse{
+		n:       s.n,
+		offsets: s.offsets,
+		cols:    s.cols,
+		vals:    make([]float64, len(s.vals)),
+	}
+	for i := 0; i < s.n; i++ {
+		for p := s.offsets[i]; p < s.offsets[i+1]; p++ {
+			res.vals[p] = d[i] * s.vals[p] * d[s.cols[p]]
+		}
+	}
+	return res
+}
@@ -0,0 +1,184 @@
+/*
+Restricts graphs to some of their nodes: those in a list, those with a
+minimum degree, and those in the k-core. The subgraph induced by the nodes
+kept, with the edges between them, is written with the lines of the input
+as they are, extra columns included.
+
+Inputs can be in any format util.OpenEdges reads. Outputs are written in the
+format of their extension, see util.CreateEdges: .txt files are written as
+CSV, even when read as whitespace separated.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/vladvelici/graph-dataset-tools/algo"
+	"github.com/vladvelici/graph-dataset-tools/graph"
+	"github.com/vladvelici/graph-dataset-tools/util"
+)
+
+var (
+	flagOutput    = flag.String("o", "sub_", "Output file prefix.")
+	flagNodes     = flag.String("nodes", "", "File with the IDs of the nodes to keep, separated by spaces, commas or new lines.")
+	flagMinDegree = flag.Int("mindegree", 0, "Keep only the nodes with at least this degree (in plus out degree in directed graphs).")
+	flagKCore     = flag.Int("kcore", 0, "Keep only the nodes of the k-core, with edges followed both ways.")
+	flagHelp      = flag.Bool("help", false, "Show this help message")
+	flagH         = flag.Bool("h", false, "Show this help message")
+)
+
+var helpMessage = `subgraph writes the subgraph induced by some nodes of graphs.
+
+Usage:
+
+subgraph [-nodes <file>] [-mindegree d] [-kcore k] [flags] <list of edge lists>
+
+Nodes are kept if they pass all the filters given, each checked on the
+whole graph. Each <file> is written to <o><file>, with the edges between
+the nodes kept.
+
+Full list of flags:
+
+`
+
+func help() {
+	fmt.Println(helpMessage)
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = help
+	flag.Parse()
+
+	if *flagHelp || *flagH {
+		help()
+		return
+	}
+
+	files := flag.Args()
+	if len(files) == 0 {
+		fmt.Println("Need at least one input graph file.")
+		return
+	}
+
+	var list []int
+	if *flagNodes != "" {
+		var err error
+		list, err = util.ReadNodeIds(*flagNodes)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	for _, f := range files {
+		g, err := readGraph(f)
+		if err != nil {
+			fmt.Printf("%s: Cannot read graph. Skipping. (%s)\n", f, err.Error())
+			continue
+		}
+		keep := keptNodes(g, list)
+		edges, err := copyEdges(f, *flagOutput+f, keep)
+		if err != nil {
+			fmt.Printf("%s: %s\n", f, err.Error())
+			continue
+		}
+		fmt.Printf("%s: Kept %d of %d nodes, and %d edges.\n", f, len(keep), len(g.Nodes), edges)
+	}
+}
+
+// Read the edges of a graph, ignoring other columns, which need not be
+// weights here.
+func readGraph(path string) (*algo.Graph, error) {
+	reader, err := util.OpenEdges(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	g := algo.NewGraph()
+	for {
+		from, to, _, err := reader.Read()
+		if err == io.EOF {
+			return g, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		g.AddDirectedEdge(from, to)
+	}
+}
+
+// The nodes of g that pass every filter. list is the -nodes list, if any:
+// with -nodes, an empty list keeps no nodes.
+func keptNodes(g *algo.Graph, list []int) map[int]bool {
+	keep := make(map[int]bool, len(g.Nodes))
+	if *flagNodes != "" {
+		for _, id := range list {
+			if g.Nodes[id] != nil {
+				keep[id] = true
+			}
+		}
+	} else {
+		for id := range g.Nodes {
+			keep[id] = true
+		}
+	}
+
+	if *flagMinDegree > 0 {
+		for _, d := range algo.Degrees(g.View()) {
+			if d.Total < *flagMinDegree {
+				delete(keep, d.Id)
+			}
+		}
+	}
+
+	if *flagKCore > 0 {
+		core := make(map[int]bool)
+		for _, id := range algo.KCore(graph.Undirected(g.View()), *flagKCore) {
+			core[id] = true
+		}
+		for id := range keep {
+			if !core[id] {
+				delete(keep, id)
+			}
+		}
+	}
+	return keep
+}
+
+// Copy the edges of the file in to the file out when both their nodes are
+// kept. Returns the number of edges copied.
+func copyEdges(in, out string, keep map[int]bool) (int, error) {
+	reader, err := util.OpenEdges(in)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	writer, err := util.CreateEdges(out)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for {
+		from, to, rubbish, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writer.Close()
+			return count, err
+		}
+		if !keep[from] || !keep[to] {
+			continue
+		}
+		if err := writer.Write(from, to, rubbish); err != nil {
+			writer.Close()
+			return count, err
+		}
+		count++
+	}
+	return count, writer.Close()
+}
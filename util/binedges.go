@@ -0,0 +1,110 @@
+package util
+
+// Binary graph files as edge lists, so every tool can read and write them.
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// BinEdgeReader reads the edges of a BinGraph, row by row.
+type BinEdgeReader struct {
+	g *BinGraph
+	i int // current row
+	p int // next edge
+}
+
+// NewBinEdgeReader reads a whole binary graph file from r.
+func NewBinEdgeReader(r io.Reader) (*BinEdgeReader, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	g, err := ParseBinGraph(data)
+	if err != nil {
+		return nil, err
+	}
+	return &BinEdgeReader{g: g}, nil
+}
+
+// Read reads an edge a b, and its weight as the only other column if the
+// graph has weights.
+func (r *BinEdgeReader) Read() (int, int, []string, error) {
+	a, b, weight, _, err := r.ReadWeighted()
+	if err != nil || r.g.Weights == nil {
+		return a, b, nil, err
+	}
+	return a, b, []string{FormatWeight(weight)}, nil
+}
+
+// ReadWeighted reads an edge a b and its weight.
+func (r *BinEdgeReader) ReadWeighted() (int, int, float64, []string, error) {
+	g := r.g
+	for r.i < g.NumNodes() && uint64(r.p) >= g.Offsets[r.i+1] {
+		r.i++
+	}
+	if r.i >= g.NumNodes() {
+		return 0, 0, 0, nil, io.EOF
+	}
+	p := r.p
+	r.p++
+	return int(g.Ids[r.i]), int(g.Ids[g.Neighbours[p]]), g.Weight(p), nil, nil
+}
+
+// Close releases the graph.
+func (r *BinEdgeReader) Close() error {
+	return r.g.Close()
+}
+
+// BinEdgeWriter collects edges and writes them as a binary graph file on
+// Flush. Edges are sorted and deduplicated like in BinGraphBuilder.
+type BinEdgeWriter struct {
+	w       io.Writer
+	b       *BinGraphBuilder
+	flushed bool
+}
+
+// NewBinEdgeWriter creates a new binary graph writer.
+func NewBinEdgeWriter(w io.Writer) *BinEdgeWriter {
+	return &BinEdgeWriter{w: w, b: NewBinGraphBuilder()}
+}
+
+// Write adds an edge a b. The first of the remaining columns, if any, is
+// the weight.
+func (w *BinEdgeWriter) Write(a, b int, rubbish []string) error {
+	if len(rubbish) == 0 {
+		return w.WriteWeighted(a, b, 1, nil)
+	}
+	weight, err := ParseWeight(rubbish[0])
+	if err != nil {
+		return err
+	}
+	return w.WriteWeighted(a, b, weight, rubbish[1:])
+}
+
+// WriteWeighted adds an edge a b with a weight.
+func (w *BinEdgeWriter) WriteWeighted(a, b int, weight float64, rubbish []string) error {
+	if w.flushed {
+		return fmt.Errorf("Binary graph writer already flushed.")
+	}
+	if len(rubbish) > 0 {
+		return fmt.Errorf("Binary graph files cannot store extra columns.")
+	}
+	w.b.Add(a, b, weight)
+	return nil
+}
+
+// Flush writes the whole file. Edges cannot be added after a Flush.
+func (w *BinEdgeWriter) Flush() error {
+	if w.flushed {
+		return nil
+	}
+	w.flushed = true
+	g, err := w.b.Build()
+	if err != nil {
+		return err
+	}
+	w.b = nil
+	return WriteBinGraph(w.w, g)
+}
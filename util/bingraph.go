@@ -0,0 +1,402 @@
+package util
+
+// A binary graph file format, so that large graphs load without parsing
+// text. The graph is stored in compressed sparse row (CSR) form, all numbers
+// little endian:
+//
+//	offset  size        field
+//	0       4           magic "GDTB"
+//	4       4           version (1)
+//	8       4           flags (bit 0: weighted)
+//	12      4           reserved, 0
+//	16      8           number of nodes N
+//	24      8           number of edges E
+//	32      8*N         node IDs (int64), sorted
+//	        8*(N+1)     row offsets (uint64) into the neighbour array
+//	        4*E         neighbours (uint32), as indices into the node IDs,
+//	                    padded with zeros to a multiple of 8 bytes
+//	        8*E         weights (float64), only if weighted
+//	        4           CRC-32 (IEEE) of everything before it
+//
+// Every section starts 8-byte aligned, so a mapped file can be used in place.
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"unsafe"
+)
+
+var binGraphMagic = []byte("GDTB")
+
+const (
+	binGraphVersion    = 1
+	binGraphHeaderSize = 32
+	binGraphWeighted   = 1 << 0
+)
+
+// BinGraph is a graph loaded from (or to be written to) a binary graph file.
+// The neighbours of node Ids[i] are Ids[j] for j in
+// Neighbours[Offsets[i]:Offsets[i+1]], sorted, with the matching Weights.
+//
+// A BinGraph is read only: when opened with OpenBinGraph, its slices may
+// point into a memory mapped file.
+type BinGraph struct {
+	Ids        []int64
+	Offsets    []uint64
+	Neighbours []uint32
+	Weights    []float64 // nil when the graph has no weights
+
+	unmap func() error
+}
+
+// NumNodes returns the number of nodes.
+func (g *BinGraph) NumNodes() int {
+	return len(g.Ids)
+}
+
+// NumEdges returns the number of (directed) edges.
+func (g *BinGraph) NumEdges() int {
+	return len(g.Neighbours)
+}
+
+// Row returns the neighbours of the i-th node, as indices into Ids.
+func (g *BinGraph) Row(i int) []uint32 {
+	return g.Neighbours[g.Offsets[i]:g.Offsets[i+1]]
+}
+
+// Weight returns the weight of the p-th edge (1 if the graph has no weights).
+func (g *BinGraph) Weight(p int) float64 {
+	if g.Weights == nil {
+		return 1
+	}
+	return g.Weights[p]
+}
+
+// Edges calls f for every edge, with node IDs, in file order.
+func (g *BinGraph) Edges(f func(a, b int, weight float64)) {
+	for i, id := range g.Ids {
+		for p := g.Offsets[i]; p < g.Offsets[i+1]; p++ {
+			f(int(id), int(g.Ids[g.Neighbours[p]]), g.Weight(int(p)))
+		}
+	}
+}
+
+// Close releases the memory mapped file, if any. The graph cannot be used
+// afterwards.
+func (g *BinGraph) Close() error {
+	if g.unmap == nil {
+		return nil
+	}
+	err := g.unmap()
+	g.unmap = nil
+	g.Ids, g.Offsets, g.Neighbours, g.Weights = nil, nil, nil, nil
+	return err
+}
+
+// BinGraphBuilder builds a BinGraph from a stream of edges.
+//
+// Repeated edges are kept once, with the weight of the last one added, not
+// the sum of their weights. All the graph readers (graph.Builder, algo.Graph,
+// the similarity adjacency of sim and mlscript/train.m) follow this rule, so
+// a file with repeated edges gives the same graph whatever reads it.
+type BinGraphBuilder struct {
+	nodes    []int64 // added without edges
+	from, to []int64
+	weights  []float64
+	weighted bool
+	repeated int
+}
+
+// NewBinGraphBuilder creates an empty builder.
+func NewBinGraphBuilder() *BinGraphBuilder {
+	return &BinGraphBuilder{}
+}
+
+// AddNode adds a node, even if it has no edges.
+func (b *BinGraphBuilder) AddNode(id int) {
+	b.nodes = append(b.nodes, int64(id))
+}
+
+// Add adds the directed edge a -> b with a weight.
+func (b *BinGraphBuilder) Add(from, to int, weight float64) {
+	b.from = append(b.from, int64(from))
+	b.to = append(b.to, int64(to))
+	b.weights = append(b.weights, weight)
+	b.weighted = b.weighted || weight != 1
+}
+
+// Build sorts the edges into a BinGraph. Repeated edges are kept once, with
+// the weight of the last one added. The graph has weights only if some edge
+// has a weight other than 1.
+func (b *BinGraphBuilder) Build() (*BinGraph, error) {
+	b.repeated = 0
+	ids := make([]int64, 0, len(b.from))
+	seen := make(map[int64]uint32)
+	for _, list := range [][]int64{b.nodes, b.from, b.to} {
+		for _, id := range list {
+			if _, ok := seen[id]; !ok {
+				seen[id] = 0
+				ids = append(ids, id)
+			}
+		}
+	}
+	if uint64(len(ids)) > math.MaxUint32 {
+		return nil, fmt.Errorf("Too many nodes (%d) for the binary graph format.", len(ids))
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for i, id := range ids {
+		seen[id] = uint32(i)
+	}
+
+	// counting sort by source, keeping the order edges were added in
+	n := len(ids)
+	start := make([]uint64, n+1)
+	for _, id := range b.from {
+		start[seen[id]+1]++
+	}
+	for i := 0; i < n; i++ {
+		start[i+1] += start[i]
+	}
+	next := make([]uint64, n)
+	copy(next, start)
+	order := make([]int, len(b.from))
+	for e, id := range b.from {
+		i := seen[id]
+		order[next[i]] = e
+		next[i]++
+	}
+
+	g := &BinGraph{
+		Ids:        ids,
+		Offsets:    make([]uint64, n+1),
+		Neighbours: make([]uint32, 0, len(order)),
+	}
+	if b.weighted {
+		g.Weights = make([]float64, 0, len(order))
+	}
+	for i := 0; i < n; i++ {
+		row := order[start[i]:start[i+1]]
+		sort.SliceStable(row, func(x, y int) bool { return b.to[row[x]] < b.to[row[y]] })
+		for k, e := range row {
+			if k+1 < len(row) && b.to[row[k+1]] == b.to[e] {
+				b.repeated++
+				continue // the last repeated edge wins
+			}
+			g.Neighbours = append(g.Neighbours, seen[b.to[e]])
+			if b.weighted {
+				g.Weights = append(g.Weights, b.weights[e])
+			}
+		}
+		g.Offsets[i+1] = uint64(len(g.Neighbours))
+	}
+	return g, nil
+}
+
+// Repeated returns how many edges the last Build dropped as repeats of
+// another edge.
+func (b *BinGraphBuilder) Repeated() int {
+	return b.repeated
+}
+
+// WriteBinGraph writes g to w in the binary graph format.
+func WriteBinGraph(w io.Writer, g *BinGraph) error {
+	buf := bufio.NewWriter(w)
+	crc := crc32.NewIEEE()
+	out := io.MultiWriter(buf, crc)
+
+	var flags uint32
+	if g.Weights != nil {
+		flags |= binGraphWeighted
+	}
+	header := make([]byte, binGraphHeaderSize)
+	copy(header, binGraphMagic)
+	binary.LittleEndian.PutUint32(header[4:], binGraphVersion)
+	binary.LittleEndian.PutUint32(header[8:], flags)
+	binary.LittleEndian.PutUint64(header[16:], uint64(len(g.Ids)))
+	binary.LittleEndian.PutUint64(header[24:], uint64(len(g.Neighbours)))
+	out.Write(header)
+
+	b := make([]byte, 8)
+	for _, id := range g.Ids {
+		binary.LittleEndian.PutUint64(b, uint64(id))
+		out.Write(b)
+	}
+	for _, off := range g.Offsets {
+		binary.LittleEndian.PutUint64(b, off)
+		out.Write(b)
+	}
+	for _, nb := range g.Neighbours {
+		binary.LittleEndian.PutUint32(b, nb)
+		out.Write(b[:4])
+	}
+	if len(g.Neighbours)%2 == 1 {
+		binary.LittleEndian.PutUint32(b, 0)
+		out.Write(b[:4])
+	}
+	for _, weight := range g.Weights {
+		binary.LittleEndian.PutUint64(b, math.Float64bits(weight))
+		out.Write(b)
+	}
+
+	binary.LittleEndian.PutUint32(b, crc.Sum32())
+	buf.Write(b[:4])
+	return buf.Flush()
+}
+
+// ParseBinGraph reads a graph from the contents of a binary graph file,
+// checking its checksum. The graph may share memory with data.
+func ParseBinGraph(data []byte) (*BinGraph, error) {
+	if len(data) < binGraphHeaderSize+4 || !bytes.Equal(data[:4], binGraphMagic) {
+		return nil, fmt.Errorf("Not a binary graph file.")
+	}
+	if v := binary.LittleEndian.Uint32(data[4:]); v != binGraphVersion {
+		return nil, fmt.Errorf("Unsupported binary graph version %d.", v)
+	}
+	flags := binary.LittleEndian.Uint32(data[8:])
+	n := binary.LittleEndian.Uint64(data[16:])
+	m := binary.LittleEndian.Uint64(data[24:])
+
+	size := uint64(binGraphHeaderSize) + 8*n + 8*(n+1) + 4*(m+m%2)
+	if flags&binGraphWeighted != 0 {
+		size += 8 * m
+	}
+	// guard against overflow from corrupted counts, too
+	if n > math.MaxUint32 || m > uint64(len(data)) || size+4 != uint64(len(data)) {
+		return nil, fmt.Errorf("Binary graph file has the wrong size for %d nodes and %d edges.", n, m)
+	}
+	if sum := binary.LittleEndian.Uint32(data[size:]); sum != crc32.ChecksumIEEE(data[:size]) {
+		return nil, fmt.Errorf("Binary graph file is corrupted (bad checksum).")
+	}
+
+	pos := uint64(binGraphHeaderSize)
+	section := func(length uint64) []byte {
+		res := data[pos : pos+length]
+		pos += length
+		return res
+	}
+	g := &BinGraph{
+		Ids:        int64s(section(8 * n)),
+		Offsets:    uint64s(section(8 * (n + 1))),
+		Neighbours: uint32s(section(4 * m)),
+	}
+	pos += 4 * (m % 2)
+	if flags&binGraphWeighted != 0 {
+		g.Weights = float64s(section(8 * m))
+	}
+
+	if g.Offsets[0] != 0 || g.Offsets[n] != m {
+		return nil, fmt.Errorf("Binary graph file has bad row offsets.")
+	}
+	for i := uint64(0); i < n; i++ {
+		if g.Offsets[i] > g.Offsets[i+1] {
+			return nil, fmt.Errorf("Binary graph file has bad row offsets.")
+		}
+	}
+	for _, nb := range g.Neighbours {
+		if uint64(nb) >= n {
+			return nil, fmt.Errorf("Binary graph file has a neighbour out of range.")
+		}
+	}
+	return g, nil
+}
+
+// OpenBinGraph loads the binary graph file at path. Where possible the file
+// is memory mapped rather than read; Close the graph to release it.
+func OpenBinGraph(path string) (*BinGraph, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	g, err := ParseBinGraph(data)
+	if err != nil {
+		if unmap != nil {
+			unmap()
+		}
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	g.unmap = unmap
+	return g, nil
+}
+
+// IsBinGraph returns whether the file at path is a binary graph file,
+// by its first bytes.
+func IsBinGraph(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	magic := make([]byte, len(binGraphMagic))
+	_, err = io.ReadFull(file, magic)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(magic, binGraphMagic), nil
+}
+
+// Whether this machine is little endian, so file sections can be used as
+// they are.
+var littleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// Use b as a []T in place when the layout allows it, otherwise decode a copy.
+func inPlace(b []byte, size uintptr) bool {
+	return littleEndian && len(b) > 0 && uintptr(unsafe.Pointer(&b[0]))%size == 0
+}
+
+func int64s(b []byte) []int64 {
+	if inPlace(b, 8) {
+		return unsafe.Slice((*int64)(unsafe.Pointer(&b[0])), len(b)/8)
+	}
+	res := make([]int64, len(b)/8)
+	for i := range res {
+		res[i] = int64(binary.LittleEndian.Uint64(b[8*i:]))
+	}
+	return res
+}
+
+func uint64s(b []byte) []uint64 {
+	if inPlace(b, 8) {
+		return unsafe.Slice((*uint64)(unsafe.Pointer(&b[0])), len(b)/8)
+	}
+	res := make([]uint64, len(b)/8)
+	for i := range res {
+		res[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	return res
+}
+
+func uint32s(b []byte) []uint32 {
+	if inPlace(b, 4) {
+		return unsafe.Slice((*uint32)(unsafe.Pointer(&b[0])), len(b)/4)
+	}
+	res := make([]uint32, len(b)/4)
+	for i := range res {
+		res[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+	return res
+}
+
+func float64s(b []byte) []float64 {
+	if inPlace(b, 8) {
+		return unsafe.Slice((*float64)(unsafe.Pointer(&b[0])), len(b)/8)
+	}
+	res := make([]float64, len(b)/8)
+	for i := range res {
+		res[i] = math.Float64frombits(binary.LittleEndian.Uint64(b[8*i:]))
+	}
+	return res
+}
@@ -0,0 +1,100 @@
+package util
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func buildTestGraph(t *testing.T) *BinGraph {
+	b := NewBinGraphBuilder()
+	b.Add(10, 3, 1)
+	b.Add(3, 10, 1)
+	b.Add(3, 7, 2)
+	b.Add(3, 5, 1)
+	b.Add(3, 7, 0.5) // repeated, this weight wins
+	g, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestBinGraphBuilder(t *testing.T) {
+	g := buildTestGraph(t)
+	if g.NumNodes() != 4 || g.NumEdges() != 4 {
+		t.Fatalf("Expected 4 nodes and 4 edges, got %d and %d.", g.NumNodes(), g.NumEdges())
+	}
+	var got []edge
+	g.Edges(func(a, b int, w float64) {
+		got = append(got, edge{a, b, w})
+	})
+	sameEdges(t, got, []edge{{3, 5, 1}, {3, 7, 0.5}, {3, 10, 1}, {10, 3, 1}})
+}
+
+func TestBinGraphRoundTrip(t *testing.T) {
+	g := buildTestGraph(t)
+	var buf bytes.Buffer
+	if err := WriteBinGraph(&buf, g); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len()%4 != 0 {
+		t.Errorf("Unexpected file size %d.", buf.Len())
+	}
+
+	read, err := ParseBinGraph(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range g.Ids {
+		if read.Ids[i] != g.Ids[i] || read.Offsets[i+1] != g.Offsets[i+1] {
+			t.Errorf("Row %d differs.", i)
+		}
+	}
+	for p := range g.Neighbours {
+		if read.Neighbours[p] != g.Neighbours[p] || read.Weight(p) != g.Weight(p) {
+			t.Errorf("Edge %d differs.", p)
+		}
+	}
+
+	data := buf.Bytes()
+	data[40] ^= 1
+	if _, err := ParseBinGraph(data); err == nil {
+		t.Error("A corrupted file should fail the checksum.")
+	}
+	if _, err := ParseBinGraph(data[:len(data)-8]); err == nil {
+		t.Error("A truncated file should fail.")
+	}
+}
+
+func TestOpenBinGraph(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bingraph")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// no .bin extension: recognised by contents
+	path := filepath.Join(dir, "graph")
+	var buf bytes.Buffer
+	if err := WriteBinGraph(&buf, buildTestGraph(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if bin, err := IsBinGraph(path); !bin || err != nil {
+		t.Fatalf("Expected a binary graph, got %t, %v.", bin, err)
+	}
+	r, err := OpenEdges(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameEdges(t, readAll(t, r), []edge{{3, 5, 1}, {3, 7, 0.5}, {3, 10, 1}, {10, 3, 1}})
+	if err := r.Close(); err != nil {
+		t.Error(err)
+	}
+}
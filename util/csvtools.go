@@ -7,6 +7,8 @@ package util
 // I don't repeat myself too much.
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/csv"
 	"fmt"
 	"io"
@@ -38,14 +40,50 @@ func (w *Writer) Write(a, b int, rubbish []string) error {
 	return w.w.Write(line)
 }
 
+// WriteWeighted writes a CSV record a,b,weight,rubbish...
+func (w *Writer) WriteWeighted(a, b int, weight float64, rubbish []string) error {
+	line := make([]string, len(rubbish)+3)
+	line[0] = strconv.Itoa(a)
+	line[1] = strconv.Itoa(b)
+	line[2] = FormatWeight(weight)
+	copy(line[3:], rubbish)
+	return w.w.Write(line)
+}
+
 // Reader is a custom CSV Reader.
 type Reader struct {
 	r *csv.Reader
 }
 
-// NewReader creates new CSV reader.
+// NewReader creates new CSV reader. Lines starting with # or % are
+// comments, and skipped.
 func NewReader(r io.Reader) *Reader {
-	return &Reader{csv.NewReader(r)}
+	return &Reader{csv.NewReader(&commentFilter{r: bufio.NewReader(r)})}
+}
+
+// commentFilter reads the lines of r that are not comments.
+type commentFilter struct {
+	r    *bufio.Reader
+	line []byte // unread part of the current line
+}
+
+func (f *commentFilter) Read(p []byte) (int, error) {
+	for len(f.line) == 0 {
+		line, err := f.r.ReadBytes('\n')
+		trimmed := bytes.TrimLeft(line, " \t")
+		if len(trimmed) == 0 || (trimmed[0] != '#' && trimmed[0] != '%') {
+			f.line = line
+		}
+		if err != nil {
+			if len(f.line) == 0 {
+				return 0, err
+			}
+			break
+		}
+	}
+	n := copy(p, f.line)
+	f.line = f.line[n:]
+	return n, nil
 }
 
 // Read reads an element.
@@ -54,6 +92,18 @@ func (r *Reader) Read() (int, int, []string, error) {
 	if err != nil {
 		return 0, 0, nil, err
 	}
+	return parseEdge(record)
+}
+
+// ReadWeighted reads an element a,b[,weight,rubbish...].
+// The third column is the weight of the edge, 1 if there is no third column
+// or if it is not a number.
+func (r *Reader) ReadWeighted() (int, int, float64, []string, error) {
+	return withWeight(r.Read())
+}
+
+// Parse the two node IDs of a record, and return the remaining columns.
+func parseEdge(record []string) (int, int, []string, error) {
 	if len(record) < 2 {
 		return 0, 0, record, fmt.Errorf("Not enough data in the record.")
 	}
@@ -72,3 +122,41 @@ func (r *Reader) Read() (int, int, []string, error) {
 	}
 	return a, b, record[2:], nil
 }
+
+// Take the weight out of the first of the remaining columns of a record.
+// A first column that is not a number is not a weight: the edge gets weight
+// 1 and keeps all its columns.
+func withWeight(a, b int, rubbish []string, err error) (int, int, float64, []string, error) {
+	if err != nil || len(rubbish) == 0 {
+		return a, b, 1, rubbish, err
+	}
+	weight, rest, err := splitWeight(rubbish)
+	if err != nil {
+		return a, b, 1, rubbish, nil
+	}
+	return a, b, weight, rest, nil
+}
+
+// Parse the first of the remaining columns of a record as the weight, and
+// return the columns after it.
+func splitWeight(rubbish []string) (float64, []string, error) {
+	weight, err := ParseWeight(rubbish[0])
+	if err != nil || len(rubbish) == 1 {
+		return weight, nil, err
+	}
+	return weight, rubbish[1:], nil
+}
+
+// ParseWeight parses an edge weight.
+func ParseWeight(s string) (float64, error) {
+	w, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("Bad edge weight %q. (%s)", s, err.Error())
+	}
+	return w, nil
+}
+
+// FormatWeight formats an edge weight, as short as possible.
+func FormatWeight(w float64) string {
+	return strconv.FormatFloat(w, 'g', -1, 64)
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadWeighted(t *testing.T) {
+	cases := []struct {
+		input   string
+		a, b    int
+		w       float64
+		rubbish int
+	}{
+		{"1, 2\n", 1, 2, 1, 0},
+		{"3, 4, 0.5\n", 3, 4, 0.5, 0},
+		{"5, 6, 2, x, y\n", 5, 6, 2, 2},
+		{"7, 8, abc\n", 7, 8, 1, 1},
+	}
+	for i, c := range cases {
+		r := NewReader(strings.NewReader(c.input))
+		a, b, w, rubbish, err := r.ReadWeighted()
+		if err != nil {
+			t.Fatalf("Record %d: %s", i, err)
+		}
+		if a != c.a || b != c.b || w != c.w || len(rubbish) != c.rubbish {
+			t.Errorf("Record %d: got (%d, %d, %f, %v), expected (%d, %d, %f, %d extra).", i, a, b, w, rubbish, c.a, c.b, c.w, c.rubbish)
+		}
+		if _, _, _, _, err := r.ReadWeighted(); err != io.EOF {
+			t.Errorf("Record %d: expected EOF, got %v.", i, err)
+		}
+	}
+
+}
+
+func TestReadWeights(t *testing.T) {
+	r := NewReader(strings.NewReader("1, 2, 0.5, x\n7, 8, abc\n"))
+	if _, _, w, rubbish, err := ReadWeights(r); err != nil || w != 0.5 || len(rubbish) != 1 {
+		t.Errorf("Expected weight 0.5 and 1 extra column, got %v, %v, %v.", w, rubbish, err)
+	}
+	if _, _, _, _, err := ReadWeights(r); err == nil {
+		t.Error("A weight that is not a number should fail.")
+	}
+}
+
+func TestWriteWeighted(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	w.WriteWeighted(1, 2, 0.25, nil)
+	w.WriteWeighted(3, 4, 3, []string{"x"})
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if s := buf.String(); s != "1,2,0.25\n3,4,3,x\n" {
+		t.Errorf("Unexpected output %q.", s)
+	}
+}
@@ -0,0 +1,266 @@
+package util
+
+// Edge list files come in a few formats. EdgeReader and EdgeWriter hide the
+// format from the tools, and OpenEdges / CreateEdges pick it from the file
+// name, so every tool reads and writes all of them.
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EdgeReader reads an edge list, one edge at a time.
+// Both methods return io.EOF after the last edge.
+type EdgeReader interface {
+	// Read reads an edge a,b and the remaining columns, if any.
+	Read() (int, int, []string, error)
+	// ReadWeighted reads an edge a,b, its weight (1 if the format or the
+	// file has none) and the remaining columns, if any. A third column that
+	// is not a number is not a weight, and stays in the remaining columns.
+	ReadWeighted() (int, int, float64, []string, error)
+}
+
+// ReadWeights reads an edge from r like r.ReadWeighted, for callers that
+// need the weights: a third column that is not a number is an error instead
+// of weight 1.
+func ReadWeights(r EdgeReader) (int, int, float64, []string, error) {
+	a, b, rubbish, err := r.Read()
+	if err != nil || len(rubbish) == 0 {
+		return a, b, 1, rubbish, err
+	}
+	weight, rest, err := splitWeight(rubbish)
+	if err != nil {
+		return a, b, 1, rubbish, err
+	}
+	return a, b, weight, rest, nil
+}
+
+// EdgeWriter writes an edge list, one edge at a time.
+type EdgeWriter interface {
+	Write(a, b int, rubbish []string) error
+	WriteWeighted(a, b int, weight float64, rubbish []string) error
+	// Flush writes any buffered data.
+	Flush() error
+}
+
+// EdgeReadCloser is an EdgeReader over an open file.
+type EdgeReadCloser interface {
+	EdgeReader
+	io.Closer
+}
+
+// EdgeWriteCloser is an EdgeWriter over an open file. Close flushes the
+// writer before closing the file.
+type EdgeWriteCloser interface {
+	EdgeWriter
+	io.Closer
+}
+
+// Format of an edge list file.
+type Format string
+
+const (
+	// Comma separated values: a,b[,weight,...]
+	FormatCSV Format = "csv"
+	// Whitespace separated values, like the SNAP datasets: a b [weight ...]
+	// Lines starting with # or % are comments.
+	FormatText Format = "text"
+	// Matrix Market coordinate format.
+	FormatMatrixMarket Format = "mtx"
+	// Binary graph file, see BinGraph.
+	FormatBinary Format = "bin"
+)
+
+// File extensions of each format. Anything else is read as CSV.
+// .txt files are CSV or whitespace separated, see OpenEdges.
+var formatExtensions = map[string]Format{
+	".csv":   FormatCSV,
+	".tsv":   FormatText,
+	".edges": FormatText,
+	".el":    FormatText,
+	".mtx":   FormatMatrixMarket,
+	".mm":    FormatMatrixMarket,
+	".bin":   FormatBinary,
+}
+
+// FormatOf returns the format of the file at path, from its extension, and
+// whether it is gzip compressed (ends in .gz).
+func FormatOf(path string) (Format, bool) {
+	ext, gz := extension(path)
+	if format, ok := formatExtensions[ext]; ok {
+		return format, gz
+	}
+	return FormatCSV, gz
+}
+
+// The lower case extension of path, without any .gz, and whether it ends
+// in .gz.
+func extension(path string) (string, bool) {
+	gz := strings.EqualFold(filepath.Ext(path), ".gz")
+	if gz {
+		path = path[:len(path)-len(".gz")]
+	}
+	return strings.ToLower(filepath.Ext(path)), gz
+}
+
+// How many bytes sniffFormat looks at.
+const sniffLength = 4096
+
+// Guess whether r holds CSV or whitespace separated edges, from its first
+// line that is not a comment, without consuming it.
+func sniffFormat(r *bufio.Reader) Format {
+	head, _ := r.Peek(sniffLength)
+	for _, line := range bytes.Split(head, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if isComment(string(line)) {
+			continue
+		}
+		if bytes.IndexByte(line, ',') < 0 {
+			return FormatText
+		}
+		break
+	}
+	return FormatCSV
+}
+
+// NewEdgeReader reads edges in the given format from r.
+func NewEdgeReader(r io.Reader, format Format) (EdgeReader, error) {
+	switch format {
+	case FormatCSV:
+		return NewReader(r), nil
+	case FormatText:
+		return NewTextReader(r), nil
+	case FormatMatrixMarket:
+		return NewMatrixMarketReader(r)
+	case FormatBinary:
+		return NewBinEdgeReader(r)
+	}
+	return nil, fmt.Errorf("Unknown edge list format %q.", format)
+}
+
+// NewEdgeWriter writes edges in the given format to w.
+func NewEdgeWriter(w io.Writer, format Format) (EdgeWriter, error) {
+	switch format {
+	case FormatCSV:
+		return NewWriter(w), nil
+	case FormatText:
+		return NewTextWriter(w), nil
+	case FormatMatrixMarket:
+		return NewMatrixMarketWriter(w), nil
+	case FormatBinary:
+		return NewBinEdgeWriter(w), nil
+	}
+	return nil, fmt.Errorf("Unknown edge list format %q.", format)
+}
+
+// OpenEdges opens the edge list at path, in the format given by FormatOf.
+// Binary graph files are recognised by their contents, whatever the name,
+// and memory mapped. .txt files are read as CSV, unless their first line
+// that is not a comment has no comma.
+func OpenEdges(path string) (EdgeReadCloser, error) {
+	bin, err := IsBinGraph(path)
+	if err != nil {
+		return nil, err
+	}
+	if bin {
+		g, err := OpenBinGraph(path)
+		if err != nil {
+			return nil, err
+		}
+		return &BinEdgeReader{g: g}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	format, gz := FormatOf(path)
+
+	res := &edgeReadCloser{closers: []io.Closer{file}}
+	var r io.Reader = file
+	if gz {
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("%s: %s", path, err.Error())
+		}
+		res.closers = append([]io.Closer{zr}, res.closers...)
+		r = zr
+	}
+	if ext, _ := extension(path); ext == ".txt" {
+		br := bufio.NewReader(r)
+		format = sniffFormat(br)
+		r = br
+	}
+
+	res.EdgeReader, err = NewEdgeReader(r, format)
+	if err != nil {
+		closeAll(res.closers)
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	return res, nil
+}
+
+// CreateEdges creates the edge list file at path, in the format given by
+// FormatOf. Unlike OpenEdges, .txt files are always written as CSV.
+func CreateEdges(path string) (EdgeWriteCloser, error) {
+	format, gz := FormatOf(path)
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &edgeWriteCloser{closers: []io.Closer{file}}
+	var w io.Writer = file
+	if gz {
+		zw := gzip.NewWriter(file)
+		res.closers = append([]io.Closer{zw}, res.closers...)
+		w = zw
+	}
+
+	res.EdgeWriter, err = NewEdgeWriter(w, format)
+	if err != nil {
+		closeAll(res.closers)
+		return nil, err
+	}
+	return res, nil
+}
+
+type edgeReadCloser struct {
+	EdgeReader
+	closers []io.Closer // innermost first
+}
+
+func (r *edgeReadCloser) Close() error {
+	return closeAll(r.closers)
+}
+
+type edgeWriteCloser struct {
+	EdgeWriter
+	closers []io.Closer // innermost first
+}
+
+func (w *edgeWriteCloser) Close() error {
+	err := w.Flush()
+	if cerr := closeAll(w.closers); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Close all of closers, returning the first error.
+func closeAll(closers []io.Closer) error {
+	var res error
+	for _, c := range closers {
+		if err := c.Close(); err != nil && res == nil {
+			res = err
+		}
+	}
+	return res
+}
@@ -0,0 +1,166 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type edge struct {
+	a, b int
+	w    float64
+}
+
+func readAll(t *testing.T, r EdgeReader) []edge {
+	var res []edge
+	for {
+		a, b, w, _, err := r.ReadWeighted()
+		if err == io.EOF {
+			return res
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		res = append(res, edge{a, b, w})
+	}
+}
+
+func sameEdges(t *testing.T, got, expected []edge) {
+	if len(got) != len(expected) {
+		t.Fatalf("Expected %v, got %v.", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Errorf("Edge %d: expected %v, got %v.", i, expected[i], got[i])
+		}
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	cases := []struct {
+		path   string
+		format Format
+		gz     bool
+	}{
+		{"graph.csv", FormatCSV, false},
+		{"graph", FormatCSV, false},
+		{"dir.txt/graph.TSV", FormatText, false},
+		{"roadNet-CA.txt.gz", FormatCSV, true},
+		{"graph.edges.gz", FormatText, true},
+		{"graph.mtx", FormatMatrixMarket, false},
+		{"graph.csv.gz", FormatCSV, true},
+	}
+	for _, c := range cases {
+		format, gz := FormatOf(c.path)
+		if format != c.format || gz != c.gz {
+			t.Errorf("%s: expected (%s, %t), got (%s, %t).", c.path, c.format, c.gz, format, gz)
+		}
+	}
+}
+
+func TestTextReader(t *testing.T) {
+	input := "# Directed graph\n% also a comment\n\n1\t2\n  3 4 0.5 x\n"
+	r := NewTextReader(strings.NewReader(input))
+	sameEdges(t, readAll(t, r), []edge{{1, 2, 1}, {3, 4, 0.5}})
+}
+
+func TestCSVComments(t *testing.T) {
+	input := "# a, \"comment\"\n% another one\n1,2,1\n  # indented\n3,4,0.5\n"
+	r := NewReader(strings.NewReader(input))
+	sameEdges(t, readAll(t, r), []edge{{1, 2, 1}, {3, 4, 0.5}})
+}
+
+// .txt files are read as CSV or whitespace separated, whatever they hold.
+func TestOpenTxt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "edges")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	inputs := map[string]string{
+		"snap.txt":  "# FromNodeId\tToNodeId\n1\t2\n3\t4\t0.5\n",
+		"comma.txt": "# from, to\n1,2,1\n3,4,0.5\n",
+	}
+	for name, input := range inputs {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(input), 0644); err != nil {
+			t.Fatal(err)
+		}
+		r, err := OpenEdges(path)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		sameEdges(t, readAll(t, r), []edge{{1, 2, 1}, {3, 4, 0.5}})
+		r.Close()
+	}
+}
+
+func TestMatrixMarketReader(t *testing.T) {
+	input := "%%MatrixMarket matrix coordinate real symmetric\n% comment\n3 3 2\n2 1 0.5\n3 3 2\n"
+	r, err := NewMatrixMarketReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameEdges(t, readAll(t, r), []edge{{2, 1, 0.5}, {1, 2, 0.5}, {3, 3, 2}})
+
+	_, err = NewMatrixMarketReader(strings.NewReader("%%MatrixMarket matrix array real general\n2 2\n"))
+	if err == nil {
+		t.Error("Dense Matrix Market files should fail.")
+	}
+}
+
+func TestMatrixMarketWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewMatrixMarketWriter(&buf)
+	w.Write(1, 3, nil)
+	w.WriteWeighted(3, 2, 0.25, nil)
+	if err := w.Write(1, 2, []string{"1", "x"}); err == nil {
+		t.Error("Extra columns should fail.")
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	expected := "%%MatrixMarket matrix coordinate real general\n3 3 2\n1 3 1\n3 2 0.25\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q.", expected, buf.String())
+	}
+}
+
+func TestOpenCreateEdges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "edges")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	edges := []edge{{1, 2, 1}, {2, 3, 1.5}, {3, 1, 1}}
+	for _, name := range []string{"g.csv", "g.tsv.gz", "g.mtx.gz", "g.mtx", "g.txt"} {
+		path := filepath.Join(dir, name)
+		w, err := CreateEdges(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, e := range edges {
+			if err := w.WriteWeighted(e.a, e.b, e.w, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := OpenEdges(path)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		sameEdges(t, readAll(t, r), edges)
+		if err := r.Close(); err != nil {
+			t.Error(err)
+		}
+	}
+}
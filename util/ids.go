@@ -0,0 +1,64 @@
+package util
+
+// Lists of node IDs, as taken by the tools that work on some nodes only.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// ParseNodeIds parses node IDs separated by commas, spaces or new lines.
+// Lines starting with # or % are comments. Repeated IDs are kept once, in
+// the order they first appear.
+func ParseNodeIds(text string) ([]int, error) {
+	seen := make(map[int]bool)
+	var ids []int
+	for _, line := range strings.Split(text, "\n") {
+		if isComment(strings.TrimSpace(line)) {
+			continue
+		}
+		for _, field := range strings.FieldsFunc(line, isIdSeparator) {
+			id, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid node ID %q. (%s)", field, err.Error())
+			}
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids, nil
+}
+
+// ReadNodeIds reads a file of node IDs, by path. See ParseNodeIds.
+func ReadNodeIds(path string) ([]int, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseNodeIds(string(raw))
+}
+
+func isIdSeparator(r rune) bool {
+	return r == ',' || r == ' ' || r == '\t' || r == '\r'
+}
+
+// The node mapping of a relabelled graph, in the format of autoincr index
+// files: Allocations[i] is the original ID of node i. Allocations[0] is unused.
+type mapping struct {
+	Allocations []int
+}
+
+// WriteMapping writes the mapping of a relabelled graph, where node i was
+// ids[i-1], as an autoincr index file.
+func WriteMapping(ids []int, fname string) error {
+	raw, err := json.Marshal(mapping{Allocations: append([]int{-1}, ids...)})
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fname, raw, 0644)
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestParseNodeIds(t *testing.T) {
+	ids, err := ParseNodeIds("# egos\n3, 1\n2 3\t4\r\n\n% more\n5\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []int{3, 1, 2, 4, 5}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected %v, got %v.", expected, ids)
+	}
+	for i := range ids {
+		if ids[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v.", expected, ids)
+		}
+	}
+
+	if _, err := ParseNodeIds("1,x"); err == nil {
+		t.Error("Expected an error for an invalid ID.")
+	}
+}
+
+func TestWriteMapping(t *testing.T) {
+	f, err := ioutil.TempFile("", "mapping")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	if err := WriteMapping([]int{7, 3}, f.Name()); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(raw) != `{"Allocations":[-1,7,3]}` {
+		t.Errorf("Unexpected mapping: %s", raw)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package util
+
+import "io/ioutil"
+
+// Read the whole file at path. Memory mapping is only done on unix.
+func mapFile(path string) ([]byte, func() error, error) {
+	data, err := ioutil.ReadFile(path)
+	return data, nil, err
+}
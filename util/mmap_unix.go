@@ -0,0 +1,38 @@
+//go:build unix
+
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"syscall"
+)
+
+// Map the file at path into memory, read only. Returns the data and a
+// function to unmap it.
+func mapFile(path string) ([]byte, func() error, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := info.Size()
+	if size == 0 || int64(int(size)) != size {
+		// nothing to map, or too large to map: let the parser complain
+		data, err := ioutil.ReadFile(path)
+		return data, nil, err
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		// some file systems cannot be mapped
+		data, err := ioutil.ReadFile(path)
+		return data, nil, err
+	}
+	return data, func() error { return syscall.Munmap(data) }, nil
+}
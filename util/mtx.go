@@ -0,0 +1,207 @@
+package util
+
+// Matrix Market coordinate files, for adjacency matrices:
+//
+//	%%MatrixMarket matrix coordinate real general
+//	% comments
+//	rows cols entries
+//	1 2 0.5
+//
+// Indices start from 1, like the node IDs of the other formats.
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const matrixMarketBanner = "%%MatrixMarket"
+
+// MatrixMarketReader reads the entries of a Matrix Market coordinate file
+// as edges. Pattern matrices have no weights. Symmetric (and skew-symmetric)
+// matrices only store one triangle, so each off-diagonal entry is read as
+// two edges, one in each direction.
+type MatrixMarketReader struct {
+	text      *TextReader
+	pattern   bool
+	symmetric bool
+	skew      bool
+	// reverse of the last edge read, still to be returned
+	pending *mtxEntry
+}
+
+type mtxEntry struct {
+	a, b    int
+	weight  float64
+	rubbish []string
+}
+
+// NewMatrixMarketReader reads the header of a Matrix Market file from r.
+func NewMatrixMarketReader(r io.Reader) (*MatrixMarketReader, error) {
+	text := NewTextReader(r)
+	if !text.s.Scan() {
+		if err := text.s.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("Empty Matrix Market file.")
+	}
+	banner := strings.Fields(strings.ToLower(text.s.Text()))
+	if len(banner) != 5 || banner[0] != strings.ToLower(matrixMarketBanner) {
+		return nil, fmt.Errorf("Not a Matrix Market file: %q.", text.s.Text())
+	}
+	if banner[1] != "matrix" || banner[2] != "coordinate" {
+		return nil, fmt.Errorf("Only Matrix Market coordinate matrices are edge lists, got %s %s.", banner[1], banner[2])
+	}
+	if banner[3] == "complex" {
+		return nil, fmt.Errorf("Complex Matrix Market files are not supported.")
+	}
+
+	res := &MatrixMarketReader{text: text, pattern: banner[3] == "pattern"}
+	switch banner[4] {
+	case "general":
+	case "symmetric", "hermitian":
+		res.symmetric = true
+	case "skew-symmetric":
+		res.symmetric = true
+		res.skew = true
+	default:
+		return nil, fmt.Errorf("Unknown Matrix Market symmetry %q.", banner[4])
+	}
+
+	// size line: rows cols entries. Not needed to read the entries.
+	for text.s.Scan() {
+		line := strings.TrimSpace(text.s.Text())
+		if isComment(line) {
+			continue
+		}
+		if len(strings.Fields(line)) != 3 {
+			return nil, fmt.Errorf("Bad Matrix Market size line %q.", line)
+		}
+		return res, nil
+	}
+	if err := text.s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("Matrix Market file without a size line.")
+}
+
+// Read reads an edge a b and the remaining columns, starting with the value
+// for matrices that are not pattern matrices.
+func (r *MatrixMarketReader) Read() (int, int, []string, error) {
+	a, b, weight, rubbish, err := r.ReadWeighted()
+	if err != nil || r.pattern {
+		return a, b, rubbish, err
+	}
+	return a, b, append([]string{FormatWeight(weight)}, rubbish...), nil
+}
+
+// ReadWeighted reads an edge a b, its value (1 for pattern matrices) and any
+// remaining columns.
+func (r *MatrixMarketReader) ReadWeighted() (int, int, float64, []string, error) {
+	if r.pending != nil {
+		e := r.pending
+		r.pending = nil
+		return e.a, e.b, e.weight, e.rubbish, nil
+	}
+
+	a, b, weight, rubbish, err := r.text.ReadWeighted()
+	if err != nil {
+		return a, b, weight, rubbish, err
+	}
+	if r.symmetric && a != b {
+		reverse := weight
+		if r.skew {
+			reverse = -weight
+		}
+		r.pending = &mtxEntry{b, a, reverse, rubbish}
+	}
+	return a, b, weight, rubbish, nil
+}
+
+// MatrixMarketWriter writes edges as a general coordinate Matrix Market file.
+//
+// The header has the number of entries, so edges are kept in memory until
+// Flush, at about 50 bytes per edge: write graphs too large for that as
+// .bin or .csv instead. The matrix is real if any edge was written with a
+// weight, and a pattern matrix otherwise. Columns after the value cannot be
+// stored.
+type MatrixMarketWriter struct {
+	w        *bufio.Writer
+	edges    []mtxEntry
+	weighted bool
+	size     int
+	flushed  bool
+}
+
+// NewMatrixMarketWriter creates a new Matrix Market writer.
+func NewMatrixMarketWriter(w io.Writer) *MatrixMarketWriter {
+	return &MatrixMarketWriter{w: bufio.NewWriter(w)}
+}
+
+// Write adds an edge a b. Like in the reader, the first of the remaining
+// columns, if any, is the value.
+func (w *MatrixMarketWriter) Write(a, b int, rubbish []string) error {
+	if len(rubbish) == 0 {
+		return w.add(a, b, 1, false, nil)
+	}
+	weight, err := ParseWeight(rubbish[0])
+	if err != nil {
+		return err
+	}
+	return w.add(a, b, weight, true, rubbish[1:])
+}
+
+// WriteWeighted adds an edge a b with a weight.
+func (w *MatrixMarketWriter) WriteWeighted(a, b int, weight float64, rubbish []string) error {
+	return w.add(a, b, weight, true, rubbish)
+}
+
+func (w *MatrixMarketWriter) add(a, b int, weight float64, weighted bool, rubbish []string) error {
+	if w.flushed {
+		return fmt.Errorf("Matrix Market writer already flushed.")
+	}
+	if len(rubbish) > 0 {
+		return fmt.Errorf("Matrix Market files cannot store extra columns.")
+	}
+	if a < 1 || b < 1 {
+		return fmt.Errorf("Matrix Market indices start from 1, got edge (%d, %d).", a, b)
+	}
+	if a > w.size {
+		w.size = a
+	}
+	if b > w.size {
+		w.size = b
+	}
+	w.weighted = w.weighted || weighted
+	w.edges = append(w.edges, mtxEntry{a: a, b: b, weight: weight})
+	return nil
+}
+
+// Flush writes the whole file. Edges cannot be added after a Flush.
+func (w *MatrixMarketWriter) Flush() error {
+	if w.flushed {
+		return w.w.Flush()
+	}
+	w.flushed = true
+
+	field := "pattern"
+	if w.weighted {
+		field = "real"
+	}
+	fmt.Fprintf(w.w, "%s matrix coordinate %s general\n", matrixMarketBanner, field)
+	fmt.Fprintf(w.w, "%d %d %d\n", w.size, w.size, len(w.edges))
+	for _, e := range w.edges {
+		w.w.WriteString(strconv.Itoa(e.a))
+		w.w.WriteByte(' ')
+		w.w.WriteString(strconv.Itoa(e.b))
+		if w.weighted {
+			w.w.WriteByte(' ')
+			w.w.WriteString(FormatWeight(e.weight))
+		}
+		w.w.WriteByte('\n')
+	}
+	w.edges = nil
+	return w.w.Flush()
+}
@@ -0,0 +1,91 @@
+package util
+
+// Whitespace separated edge lists, as used by the SNAP datasets:
+//
+//	# comment
+//	1	2
+//	1	3	0.5
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Longest line a TextReader accepts.
+const maxLineLength = 1 << 20
+
+// TextReader reads whitespace separated edge lists, skipping empty lines
+// and comment lines (starting with # or %).
+type TextReader struct {
+	s *bufio.Scanner
+}
+
+// NewTextReader creates a new whitespace separated edge list reader.
+func NewTextReader(r io.Reader) *TextReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), maxLineLength)
+	return &TextReader{s}
+}
+
+// Read reads an edge a b [rubbish...].
+func (r *TextReader) Read() (int, int, []string, error) {
+	for r.s.Scan() {
+		line := strings.TrimSpace(r.s.Text())
+		if isComment(line) {
+			continue
+		}
+		return parseEdge(strings.Fields(line))
+	}
+	if err := r.s.Err(); err != nil {
+		return 0, 0, nil, err
+	}
+	return 0, 0, nil, io.EOF
+}
+
+// ReadWeighted reads an edge a b [weight rubbish...].
+// The third column is the weight of the edge, 1 if there is no third column
+// or if it is not a number.
+func (r *TextReader) ReadWeighted() (int, int, float64, []string, error) {
+	return withWeight(r.Read())
+}
+
+// Whether a trimmed line has no data.
+func isComment(line string) bool {
+	return line == "" || line[0] == '#' || line[0] == '%'
+}
+
+// TextWriter writes tab separated edge lists.
+type TextWriter struct {
+	w *bufio.Writer
+}
+
+// NewTextWriter creates a new tab separated edge list writer.
+func NewTextWriter(w io.Writer) *TextWriter {
+	return &TextWriter{bufio.NewWriter(w)}
+}
+
+func (w *TextWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// Write writes a line a b rubbish...
+func (w *TextWriter) Write(a, b int, rubbish []string) error {
+	w.w.WriteString(strconv.Itoa(a))
+	w.w.WriteByte('\t')
+	w.w.WriteString(strconv.Itoa(b))
+	for _, s := range rubbish {
+		w.w.WriteByte('\t')
+		w.w.WriteString(s)
+	}
+	return w.w.WriteByte('\n')
+}
+
+// WriteWeighted writes a line a b weight rubbish...
+func (w *TextWriter) WriteWeighted(a, b int, weight float64, rubbish []string) error {
+	line := make([]string, len(rubbish)+1)
+	line[0] = FormatWeight(weight)
+	copy(line[1:], rubbish)
+	return w.Write(a, b, line)
+}